@@ -11,4 +11,6 @@ func main() {
 	}
 
 	plugin.Serve(&p)
+
+	zabbix.CloseSessions()
 }
@@ -1,25 +1,127 @@
 package zabbix
 
 import (
-	"encoding/json"
-	//"errors"
 	"fmt"
 	"log"
-	//"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
-	//"github.com/mcuadros/go-version"
 )
 
+// hostRecord is a host returned by host.get with its interfaces, groups,
+// templates, macros, tags and inventory
+type hostRecord struct {
+	HostID        string `json:"hostid"`
+	Host          string `json:"host"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	ProxyHostID   string `json:"proxy_hostid"`
+	ProxyID       string `json:"proxyid"`
+	InventoryMode string `json:"inventory_mode"`
+
+	Interfaces []hostInterface `json:"interfaces"`
+	Groups     []zabbixGroup   `json:"groups"`
+	HostGroups []zabbixGroup   `json:"hostgroups"`
+	Templates  []struct {
+		TemplateID string `json:"templateid"`
+		Host       string `json:"host"`
+	} `json:"parentTemplates"`
+	Macros    zabbix.Macros `json:"macros"`
+	Tags      zabbix.Tags   `json:"tags"`
+	Inventory hostInventory `json:"inventory"`
+}
+
+// proxyID return the proxy monitoring the host, proxy_hostid was renamed
+// proxyid in Zabbix 7.0
+func (h *hostRecord) proxyID() string {
+	if h.ProxyID != "" {
+		return h.ProxyID
+	}
+	return h.ProxyHostID
+}
+
+func (h *hostRecord) groups() []zabbixGroup {
+	if len(h.HostGroups) > 0 {
+		return h.HostGroups
+	}
+	return h.Groups
+}
+
+// mainInterfaceID return the main agent interface, or the first main
+// interface of hosts without agent interface
+func (h *hostRecord) mainInterfaceID() string {
+	agent := fmt.Sprintf("%d", HostInterfaceTypes["agent"])
+	id := ""
+	for _, i := range h.Interfaces {
+		if i.Main != "1" {
+			continue
+		}
+		if i.Type == agent {
+			return i.InterfaceID
+		}
+		if id == "" {
+			id = i.InterfaceID
+		}
+	}
+	return id
+}
+
+// getHostRecords fetch the hosts matching params, with every object used by
+// the host data sources selected in the same call
+func getHostRecords(m *providerMeta, params zabbix.Params) ([]hostRecord, error) {
+	// proxy_hostid was renamed proxyid with the proxy groups
+	if m.supports(capabilityProxyGroups) {
+		params["output"] = []string{"hostid", "host", "name", "status", "proxyid", "inventory_mode"}
+	} else {
+		params["output"] = []string{"hostid", "host", "name", "status", "proxy_hostid", "inventory_mode"}
+	}
+	params["selectInterfaces"] = "extend"
+	params["selectParentTemplates"] = []string{"templateid", "host"}
+	params["selectMacros"] = []string{"macro", "value"}
+	params["selectTags"] = []string{"tag", "value"}
+	params["selectInventory"] = hostInventoryFields
+
+	// host groups are selected with selectHostGroups since the split of the
+	// template groups
+	if m.supports(capabilityTemplateGroups) {
+		params["selectHostGroups"] = []string{"groupid", "name"}
+	} else {
+		params["selectGroups"] = []string{"groupid", "name"}
+	}
+
+	var hosts []hostRecord
+	err := m.api.CallWithErrorParse("host.get", params, &hosts)
+	return hosts, err
+}
+
 func dataSourceZabbixHost() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceZabbixHostRead,
 		Schema: map[string]*schema.Schema{
 			"host_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"host_id", "host", "name", "tag"},
+				Description:  "ID of the host",
+			},
+			"host": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "ID of the host",
+				Optional:    true,
+				Computed:    true,
+				Description: "Technical name of the host",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Visible name of the host",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        hostTagFilterSchema,
+				Description: "Tag conditions the host must all match",
 			},
 			"main_interface_id": &schema.Schema{
 				Type:        schema.TypeString,
@@ -31,60 +133,142 @@ func dataSourceZabbixHost() *schema.Resource {
 				Elem:     interfaceSchema,
 				Computed: true,
 			},
+			"groups": &schema.Schema{
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+			"group_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+			"templates": &schema.Schema{
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+			"macro": &schema.Schema{
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "User macros of the host, the values of secret macros are empty",
+			},
 			"tags": &schema.Schema{
 				Type:     schema.TypeMap,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Computed: true,
 			},
+			"proxy_hostid": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the proxy monitoring the host, 0 when monitored by the server",
+			},
+			"monitored": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"inventory_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"inventory": &schema.Schema{
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
 		},
 	}
 }
 
-func dataSourceZabbixHostRead(d *schema.ResourceData, meta interface{}) (err error) {
-	host_id := d.Get("host_id").(string)
-
-	d.SetId(host_id)
+func dataSourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
+	params := zabbix.Params{}
+	filter := map[string]interface{}{}
 
-	api := meta.(*zabbix.API)
-	log.Printf("[DEBUG] Will read host with id %s", d.Get("host_id").(string))
+	if v, ok := d.GetOk("host_id"); ok {
+		params["hostids"] = []string{v.(string)}
+	}
+	if v, ok := d.GetOk("host"); ok {
+		filter["host"] = v.(string)
+	}
+	if v, ok := d.GetOk("name"); ok {
+		filter["name"] = v.(string)
+	}
+	if len(filter) > 0 {
+		params["filter"] = filter
+	}
+	if v, ok := d.GetOk("tag"); ok {
+		params["tags"] = createHostTagFilters(v.([]interface{}))
+	}
 
-	host, err := api.HostGetByID(d.Get("host_id").(string))
+	log.Printf("[DEBUG] Will read host matching %v", params)
 
+	hosts, err := getHostRecords(meta.(*providerMeta), params)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read host")
 	}
 
-	interfaces_str, err := json.Marshal(host.Interfaces)
-	log.Printf("[DEBUG] host interfaces =  %s", fmt.Sprintf(string(interfaces_str)))
+	switch len(hosts) {
+	case 0:
+		return fmt.Errorf("No host matches the lookup")
+	case 1:
+	default:
+		names := make([]string, len(hosts))
+		for i, h := range hosts {
+			names[i] = h.Host
+		}
+		return fmt.Errorf("%d hosts match the lookup, expected one: %v", len(hosts), names)
+	}
 
-	//d.Set("interfaces", host.Interfaces)
+	return setHostRecord(d, &hosts[0])
+}
 
-	// single id
-	log.Printf("[DEBUG] testing host.Interfaces[0].InterfaceId =  %d", host.Interfaces[0].InterfaceId)
-	d.Set("main_interface_id", fmt.Sprintf("%d", host.Interfaces[0].InterfaceId))
+func setHostRecord(d *schema.ResourceData, host *hostRecord) error {
+	d.SetId(host.HostID)
+	d.Set("host_id", host.HostID)
+	d.Set("host", host.Host)
+	d.Set("name", host.Name)
+	d.Set("main_interface_id", host.mainInterfaceID())
+	d.Set("interfaces", flattenHostInterfaces(host.Interfaces))
+	d.Set("proxy_hostid", host.proxyID())
+	d.Set("monitored", host.Status == "0")
+	d.Set("inventory_mode", hostInventoryModeName(host.InventoryMode))
 
-	// get all interfaces
-	interfaceCount := len(host.Interfaces)
-	log.Printf("[DEBUG] interfaceCount =  %d", interfaceCount)
-	interfaces := make(zabbix.HostInterfaces, interfaceCount)
+	inventory := make(map[string]interface{})
+	for name, value := range host.Inventory {
+		if value != "" && isHostInventoryField(name) {
+			inventory[name] = value
+		}
+	}
+	d.Set("inventory", inventory)
 
-	for i := 0; i < interfaceCount; i++ {
-		prefix := fmt.Sprintf("interfaces.%d.", i)
-		log.Printf("[DEBUG] Interface id =  %d", i)
-		log.Printf("[DEBUG] Prefix id =  %s", prefix)
+	var groupNames, groupIDs []string
+	for _, g := range host.groups() {
+		groupNames = append(groupNames, g.Name)
+		groupIDs = append(groupIDs, g.GroupID)
+	}
+	d.Set("groups", groupNames)
+	d.Set("group_ids", groupIDs)
 
-		interfaces[i] = zabbix.HostInterface{
-			//IP:    ip,
-			//DNS:   dns,
-			//Main:  main,
-			//Port:  d.Get(prefix + "port").(string),
-			//Type:  typeID,
-			//UseIP: useip,
-		}
+	templateNames := make([]string, len(host.Templates))
+	for i, t := range host.Templates {
+		templateNames[i] = t.Host
 	}
+	d.Set("templates", templateNames)
 
-	d.Set("interfaces", interfaces)
+	h := &zabbix.Host{UserMacros: host.Macros, Tags: host.Tags}
+
+	terraformTags, err := createTerraformTag(h)
+	if err != nil {
+		return err
+	}
+	d.Set("tags", terraformTags)
+
+	terraformMacros, err := createTerraformMacroHost(h)
+	if err != nil {
+		return err
+	}
+	d.Set("macro", terraformMacros)
 
-	d.Set("tags", host.Tags)
 	return nil
 }
@@ -0,0 +1,139 @@
+package zabbix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccZabbixDataSourceHost_basic(t *testing.T) {
+	strID := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", strID)
+	groupName := fmt.Sprintf("host_group_%s", strID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixDataSourceHostConfig(host, groupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.zabbix_host.by_name", "host_id", "zabbix_host.zabbix1", "id"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "name", host),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "monitored", "true"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "interfaces.#", "1"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "interfaces.0.ip", "127.0.0.1"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "interfaces.0.port", "10050"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "interfaces.0.type", "agent"),
+					resource.TestCheckResourceAttrPair("data.zabbix_host.by_name", "main_interface_id", "zabbix_host.zabbix1", "interfaces.0.interface_id"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_name", "groups.#", "1"),
+					resource.TestCheckResourceAttrPair("data.zabbix_host.by_id", "host", "zabbix_host.zabbix1", "host"),
+				),
+			},
+		},
+	})
+}
+
+func TestHostRecordMainInterfaceID(t *testing.T) {
+	cases := []struct {
+		interfaces []hostInterface
+		expected   string
+	}{
+		{nil, ""},
+		{[]hostInterface{{InterfaceID: "1", Type: "2", Main: "1"}, {InterfaceID: "2", Type: "1", Main: "1"}}, "2"},
+		{[]hostInterface{{InterfaceID: "1", Type: "1", Main: "0"}, {InterfaceID: "2", Type: "2", Main: "1"}}, "2"},
+	}
+
+	for _, c := range cases {
+		host := hostRecord{Interfaces: c.interfaces}
+		if id := host.mainInterfaceID(); id != c.expected {
+			t.Errorf("Got main interface %q for %v, expected %q", id, c.interfaces, c.expected)
+		}
+	}
+}
+
+func testAccZabbixDataSourceHostConfig(host string, hostGroup string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip = "127.0.0.1"
+				main = true
+			}
+			groups = ["${zabbix_host_group.zabbix.name}"]
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}
+
+		data "zabbix_host" "by_name" {
+			host = zabbix_host.zabbix1.host
+		}
+
+		data "zabbix_host" "by_id" {
+			host_id = zabbix_host.zabbix1.id
+		}`, host, hostGroup,
+	)
+}
+
+func TestAccZabbixDataSourceHosts_basic(t *testing.T) {
+	strID := acctest.RandString(5)
+	groupName := fmt.Sprintf("host_group_%s", strID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixDataSourceHostsConfig(strID, groupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zabbix_hosts.group", "hosts.#", "2"),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.group", "hosts.0.host", fmt.Sprintf("host_a_%s", strID)),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.group", "hosts.0.interfaces.0.ip", "127.0.0.1"),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.search", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.search", "hosts.0.host", fmt.Sprintf("host_b_%s", strID)),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixDataSourceHostsConfig(strID string, hostGroup string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "a" {
+			host = "host_a_%[1]s"
+			interfaces {
+				ip = "127.0.0.1"
+				main = true
+			}
+			groups = [zabbix_host_group.zabbix.name]
+		}
+
+		resource "zabbix_host" "b" {
+			host = "host_b_%[1]s"
+			interfaces {
+				ip = "127.0.0.2"
+				main = true
+			}
+			groups = [zabbix_host_group.zabbix.name]
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%[2]s"
+		}
+
+		data "zabbix_hosts" "group" {
+			group_ids  = [zabbix_host_group.zabbix.id]
+			depends_on = [zabbix_host.a, zabbix_host.b]
+		}
+
+		data "zabbix_hosts" "search" {
+			groups      = [zabbix_host_group.zabbix.name]
+			host_search = "host_b_*"
+			depends_on  = [zabbix_host.a, zabbix_host.b]
+		}`, strID, hostGroup,
+	)
+}
@@ -0,0 +1,296 @@
+package zabbix
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// HostTagsEvalTypes zabbix different evaluation of the tag conditions of host.get
+var HostTagsEvalTypes = map[string]int{
+	"and_or": 0,
+	"or":     2,
+}
+
+var hostsDataSourceHostSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"host_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"host": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"monitored": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"proxy_hostid": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"main_interface_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"interfaces": &schema.Schema{
+			Type:     schema.TypeList,
+			Elem:     interfaceSchema,
+			Computed: true,
+		},
+		"groups": &schema.Schema{
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Computed: true,
+		},
+		"group_ids": &schema.Schema{
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Computed: true,
+		},
+		"templates": &schema.Schema{
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Computed: true,
+		},
+		"tags": &schema.Schema{
+			Type:     schema.TypeMap,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Computed: true,
+		},
+	},
+}
+
+func dataSourceZabbixHosts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceZabbixHostsRead,
+		Schema: map[string]*schema.Schema{
+			"groups": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Names of host groups, the hosts must belong to one of the groups",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of host groups, the hosts must belong to one of the groups",
+			},
+			"templates": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Technical names of templates, the hosts must be linked to one of the templates",
+			},
+			"template_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of templates, the hosts must be linked to one of the templates",
+			},
+			"proxies": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Names of proxies, the hosts must be monitored by one of the proxies",
+			},
+			"proxy_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of proxies, the hosts must be monitored by one of the proxies",
+			},
+			"monitored": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Status of the hosts: true for monitored hosts, false for unmonitored ones, all hosts when empty",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if v := val.(string); v != "" && v != "true" && v != "false" {
+						errs = append(errs, fmt.Errorf("%q must be true, false or empty, got %q", key, v))
+					}
+					return
+				},
+			},
+			"host_search": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Pattern the technical name of the hosts must match, * is a wildcard",
+			},
+			"name_search": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Pattern the visible name of the hosts must match, * is a wildcard",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        hostTagFilterSchema,
+				Description: "Tag conditions of the hosts",
+			},
+			"tags_evaltype": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "and_or",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if _, ok := HostTagsEvalTypes[val.(string)]; !ok {
+						errs = append(errs, fmt.Errorf("%q must be and_or or or, got %q", key, val))
+					}
+					return
+				},
+				Description: "Evaluation of the tag conditions: and_or (conditions on the same tag are or'ed, the other ones and'ed) or or",
+			},
+			"hosts": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        hostsDataSourceHostSchema,
+				Computed:    true,
+				Description: "Hosts matching the filters, ordered by technical name",
+			},
+		},
+	}
+}
+
+// filterIDs return the ids and the resolved names of a pair of filters
+func filterIDs(d *schema.ResourceData, api *zabbix.API, idsKey, namesKey, method, idField, nameField string) ([]string, error) {
+	ids := expandStringSet(d.Get(idsKey).(*schema.Set))
+
+	names := expandStringSet(d.Get(namesKey).(*schema.Set))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	resolved, err := lookupObjects(api, method, nameField, idField, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		ids = append(ids, resolved[name])
+	}
+	return ids, nil
+}
+
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, set.Len())
+	for i, v := range set.List() {
+		values[i] = v.(string)
+	}
+	return values
+}
+
+func dataSourceZabbixHostsRead(d *schema.ResourceData, meta interface{}) error {
+	m := meta.(*providerMeta)
+
+	params := zabbix.Params{
+		"sortfield": "host",
+	}
+
+	groupIDs, err := filterIDs(d, m.api, "group_ids", "groups", "hostgroup.get", "groupid", "name")
+	if err != nil {
+		return err
+	}
+	if len(groupIDs) > 0 {
+		params["groupids"] = groupIDs
+	}
+
+	templateIDs, err := filterIDs(d, m.api, "template_ids", "templates", "template.get", "templateid", "host")
+	if err != nil {
+		return err
+	}
+	if len(templateIDs) > 0 {
+		params["templateids"] = templateIDs
+	}
+
+	proxyIDs, err := filterIDs(d, m.api, "proxy_ids", "proxies", "proxy.get", "proxyid", proxyNameField(m))
+	if err != nil {
+		return err
+	}
+	if len(proxyIDs) > 0 {
+		params["proxyids"] = proxyIDs
+	}
+
+	switch d.Get("monitored").(string) {
+	case "true":
+		params["filter"] = map[string]interface{}{"status": "0"}
+	case "false":
+		params["filter"] = map[string]interface{}{"status": "1"}
+	}
+
+	search := map[string]interface{}{}
+	if v, ok := d.GetOk("host_search"); ok {
+		search["host"] = v.(string)
+	}
+	if v, ok := d.GetOk("name_search"); ok {
+		search["name"] = v.(string)
+	}
+	if len(search) > 0 {
+		params["search"] = search
+		params["searchWildcardsEnabled"] = true
+	}
+
+	if v, ok := d.GetOk("tag"); ok {
+		params["tags"] = createHostTagFilters(v.([]interface{}))
+		params["evaltype"] = HostTagsEvalTypes[d.Get("tags_evaltype").(string)]
+	}
+
+	log.Printf("[DEBUG] Will read hosts matching %v", params)
+
+	hosts, err := getHostRecords(m, params)
+	if err != nil {
+		return describeAPIError(err, "Failed to read hosts")
+	}
+
+	ids := make([]string, len(hosts))
+	list := make([]interface{}, len(hosts))
+	for i := range hosts {
+		ids[i] = hosts[i].HostID
+		list[i], err = flattenHostRecord(&hosts[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%d", hashcode.String(strings.Join(ids, ","))))
+	return d.Set("hosts", list)
+}
+
+func flattenHostRecord(host *hostRecord) (map[string]interface{}, error) {
+	var groupNames, groupIDs []interface{}
+	for _, g := range host.groups() {
+		groupNames = append(groupNames, g.Name)
+		groupIDs = append(groupIDs, g.GroupID)
+	}
+
+	templateNames := make([]interface{}, len(host.Templates))
+	for i, t := range host.Templates {
+		templateNames[i] = t.Host
+	}
+
+	tags, err := createTerraformTag(&zabbix.Host{Tags: host.Tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"host_id":           host.HostID,
+		"host":              host.Host,
+		"name":              host.Name,
+		"monitored":         host.Status == "0",
+		"proxy_hostid":      host.proxyID(),
+		"main_interface_id": host.mainInterfaceID(),
+		"interfaces":        flattenHostInterfaces(host.Interfaces),
+		"groups":            schema.NewSet(schema.HashString, groupNames),
+		"group_ids":         schema.NewSet(schema.HashString, groupIDs),
+		"templates":         schema.NewSet(schema.HashString, templateNames),
+		"tags":              tags,
+	}, nil
+}
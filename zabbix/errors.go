@@ -0,0 +1,159 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nzolot/go-zabbix-api"
+)
+
+type errorCategory int
+
+const (
+	errorUnknown errorCategory = iota
+	errorNotFound
+	errorPermissionDenied
+	errorAlreadyExists
+	errorInvalidParams
+	errorDB
+	errorSessionExpired
+)
+
+func (c errorCategory) String() string {
+	switch c {
+	case errorNotFound:
+		return "object not found"
+	case errorPermissionDenied:
+		return "permission denied"
+	case errorAlreadyExists:
+		return "object already exists"
+	case errorInvalidParams:
+		return "invalid parameters"
+	case errorDB:
+		return "database error"
+	case errorSessionExpired:
+		return "session expired"
+	}
+	return "API error"
+}
+
+// JSON-RPC error codes returned by the zabbix API
+const (
+	jsonRPCInvalidParams    = -32602
+	jsonRPCApplicationError = -32500
+)
+
+// apiError is an error of the zabbix API sorted in a category, so resources
+// don't need to match the error messages themselves
+type apiError struct {
+	category errorCategory
+	code     int
+	message  string
+	data     string
+	err      error
+}
+
+func (e *apiError) Error() string {
+	if e.code != 0 {
+		return fmt.Sprintf("Zabbix API %s: %s (code %d, %s)", e.category, e.data, e.code, strings.TrimSuffix(e.message, "."))
+	}
+	return fmt.Sprintf("Zabbix API %s: %s", e.category, e.data)
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// errorMessages are the messages identifying a category, checked in order
+var errorMessages = []struct {
+	category errorCategory
+	messages []string
+}{
+	{errorSessionExpired, []string{"Session terminated", "re-login", "Not authorised", "Not authorized", "API token expired"}},
+	{errorNotFound, []string{"Expected exactly one result, got 0", "No permissions to referred object or it does not exist", "does not exist", "doesn't exist"}},
+	{errorPermissionDenied, []string{"No permissions", "You do not have permission", "Permission denied"}},
+	{errorAlreadyExists, []string{"already exists"}},
+	{errorDB, transientDBErrors},
+}
+
+func categorizeErrorMessage(message string) errorCategory {
+	for _, c := range errorMessages {
+		for _, m := range c.messages {
+			if strings.Contains(message, m) {
+				return c.category
+			}
+		}
+	}
+	return errorUnknown
+}
+
+// parseAPIError sort err in a category, err is returned as is when it already is an apiError
+func parseAPIError(err error) *apiError {
+	if err == nil {
+		return nil
+	}
+
+	var categorized *apiError
+	if errors.As(err, &categorized) {
+		return categorized
+	}
+
+	var rpcError *zabbix.Error
+	if errors.As(err, &rpcError) {
+		parsed := &apiError{
+			category: categorizeErrorMessage(rpcError.Data + " " + rpcError.Message),
+			code:     rpcError.Code,
+			message:  rpcError.Message,
+			data:     rpcError.Data,
+			err:      err,
+		}
+		if parsed.category == errorUnknown {
+			switch rpcError.Code {
+			case jsonRPCInvalidParams:
+				parsed.category = errorInvalidParams
+			case jsonRPCApplicationError:
+				if strings.Contains(rpcError.Data, "SQL") || strings.Contains(rpcError.Data, "DB") {
+					parsed.category = errorDB
+				}
+			}
+		}
+		return parsed
+	}
+
+	return &apiError{
+		category: categorizeErrorMessage(err.Error()),
+		data:     err.Error(),
+		err:      err,
+	}
+}
+
+func newNotFoundError(format string, a ...interface{}) error {
+	return &apiError{
+		category: errorNotFound,
+		data:     fmt.Sprintf(format, a...),
+	}
+}
+
+func isErrorCategory(err error, category errorCategory) bool {
+	if err == nil {
+		return false
+	}
+	return parseAPIError(err).category == category
+}
+
+func isNotFoundError(err error) bool {
+	return isErrorCategory(err, errorNotFound)
+}
+
+func isSessionExpiredError(err error) bool {
+	return isErrorCategory(err, errorSessionExpired)
+}
+
+// describeAPIError prefix the categorized err with what the provider was doing
+func describeAPIError(err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), parseAPIError(err))
+}
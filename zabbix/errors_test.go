@@ -0,0 +1,47 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nzolot/go-zabbix-api"
+)
+
+func TestParseAPIError(t *testing.T) {
+	expectedOneResult := zabbix.ExpectedOneResult(0)
+
+	cases := []struct {
+		err      error
+		expected errorCategory
+	}{
+		{&zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "No permissions to referred object or it does not exist!"}, errorNotFound},
+		{&zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "Session terminated, re-login, please."}, errorSessionExpired},
+		{&zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "Host with the same name \"web01\" already exists."}, errorAlreadyExists},
+		{&zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "Invalid parameter \"/1\": unexpected parameter \"foo\"."}, errorInvalidParams},
+		{&zabbix.Error{Code: -32500, Message: "Application error.", Data: "SQL statement execution has failed \"UPDATE items ...\": Deadlock found when trying to get lock"}, errorDB},
+		{&zabbix.Error{Code: -32500, Message: "Application error.", Data: "You do not have permission to perform this operation."}, errorPermissionDenied},
+		{&expectedOneResult, errorNotFound},
+		{fmt.Errorf("Failed to read host 10084: %w", &zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "Not authorised."}), errorSessionExpired},
+		{newNotFoundError("Trigger %s doesn't exist", "42"), errorNotFound},
+		{errors.New("connection refused"), errorUnknown},
+	}
+
+	for _, c := range cases {
+		if category := parseAPIError(c.err).category; category != c.expected {
+			t.Errorf("%q: got category %s, expected %s", c.err.Error(), category, c.expected)
+		}
+	}
+}
+
+func TestDescribeAPIError(t *testing.T) {
+	err := describeAPIError(&zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "No permissions to referred object or it does not exist!"}, "Failed to read host %s", "10084")
+
+	if !isNotFoundError(err) {
+		t.Fatalf("Expected %q to be a not found error", err.Error())
+	}
+	if !strings.HasPrefix(err.Error(), "Failed to read host 10084: Zabbix API object not found") {
+		t.Fatalf("Unexpected error message %q", err.Error())
+	}
+}
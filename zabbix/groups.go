@@ -0,0 +1,159 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// groupAPI is the API of the groups of hosts, or of templates since Zabbix 6.2
+type groupAPI struct {
+	kind     string
+	prefix   string
+	objectID string
+}
+
+var hostGroupAPI = groupAPI{kind: "Host group", prefix: "hostgroup", objectID: "hostids"}
+
+var templateGroupAPI = groupAPI{kind: "Template group", prefix: "templategroup", objectID: "templateids"}
+
+// templateGroupsAPI return the API of the groups of the templates, which are
+// host groups before Zabbix 6.2
+func templateGroupsAPI(m *providerMeta) groupAPI {
+	if m.supports(capabilityTemplateGroups) {
+		return templateGroupAPI
+	}
+	return hostGroupAPI
+}
+
+type zabbixGroup struct {
+	GroupID string `json:"groupid"`
+	Name    string `json:"name"`
+}
+
+// getGroupIDs resolve the groups and group_ids attributes to group ids, the
+// groups missing are created when create_missing_groups is set
+func getGroupIDs(d *schema.ResourceData, api *zabbix.API, groupsAPI groupAPI) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range d.Get("group_ids").(*schema.Set).List() {
+		add(id.(string))
+	}
+
+	configGroups := d.Get("groups").(*schema.Set)
+	names := make([]string, configGroups.Len())
+	for i, g := range configGroups.List() {
+		names[i] = g.(string)
+	}
+
+	log.Printf("[DEBUG] Groups %v, group ids %v\n", names, ids)
+
+	if len(names) > 0 {
+		var groups []zabbixGroup
+		err := api.CallWithErrorParse(groupsAPI.prefix+".get", zabbix.Params{
+			"output": []string{"groupid", "name"},
+			"filter": map[string]interface{}{
+				"name": names,
+			},
+		}, &groups)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range names {
+			found := false
+
+			for _, g := range groups {
+				if n == g.Name {
+					add(g.GroupID)
+					found = true
+					break
+				}
+			}
+
+			if found {
+				continue
+			}
+
+			if !d.Get("create_missing_groups").(bool) {
+				return nil, fmt.Errorf("%s %s doesnt exist in zabbix server", groupsAPI.kind, n)
+			}
+
+			id, err := createGroup(api, groupsAPI, n)
+			if err != nil {
+				return nil, describeAPIError(err, "Failed to create %s %s", groupsAPI.kind, n)
+			}
+			log.Printf("[DEBUG] Created %s %s with id %s", groupsAPI.kind, n, id)
+			add(id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("At least one group must be set in groups or group_ids")
+	}
+
+	return ids, nil
+}
+
+func createGroup(api *zabbix.API, groupsAPI groupAPI, name string) (string, error) {
+	var result struct {
+		GroupIDs []string `json:"groupids"`
+	}
+
+	err := api.CallWithErrorParse(groupsAPI.prefix+".create", []map[string]string{
+		{"name": name},
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.GroupIDs) != 1 {
+		return "", fmt.Errorf("Expected one group id to be created, got %d", len(result.GroupIDs))
+	}
+	return result.GroupIDs[0], nil
+}
+
+// readGroups set the groups of the object id in groups and group_ids. The groups
+// referenced by id in state stay in group_ids, the other ones are set by name
+func readGroups(d *schema.ResourceData, api *zabbix.API, groupsAPI groupAPI, id string) error {
+	var groups []zabbixGroup
+
+	err := api.CallWithErrorParse(groupsAPI.prefix+".get", zabbix.Params{
+		"output":           []string{"groupid", "name"},
+		groupsAPI.objectID: []string{id},
+	}, &groups)
+	if err != nil {
+		return err
+	}
+
+	stateIDs := d.Get("group_ids").(*schema.Set)
+	stateNames := d.Get("groups").(*schema.Set)
+
+	names := []string{}
+	ids := []string{}
+	for _, g := range groups {
+		// a group referenced both by id and by name stays in both
+		if stateIDs.Contains(g.GroupID) {
+			ids = append(ids, g.GroupID)
+			if stateNames.Contains(g.Name) {
+				names = append(names, g.Name)
+			}
+		} else {
+			names = append(names, g.Name)
+		}
+	}
+
+	d.Set("groups", names)
+	d.Set("group_ids", ids)
+
+	return nil
+}
@@ -3,6 +3,7 @@ package zabbix
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,21 +12,67 @@ import (
 	"github.com/nzolot/go-zabbix-api"
 )
 
+// defaultTimeout is the default create, update and delete timeout of the resources
+const defaultTimeout = time.Minute
+
+// transientDBErrors are the messages of database errors which can succeed when retried
+var transientDBErrors = []string{
+	"SQL statement execution",
+	"DBEXECUTE_ERROR",
+	"Deadlock found",
+	"deadlock detected",
+	"Lock wait timeout exceeded",
+	"could not serialize access",
+}
+
 func sqlError(err error) bool {
-	if strings.Contains(err.Error(), "SQL statement execution") || strings.Contains(err.Error(), "DBEXECUTE_ERROR") {
-		return true
+	return isErrorCategory(err, errorDB)
+}
+
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultTimeout),
+		Update: schema.DefaultTimeout(defaultTimeout),
+		Delete: schema.DefaultTimeout(defaultTimeout),
 	}
-	return false
 }
 
 type deleteFunc func([]string) ([]interface{}, error)
 type createFunc func(interface{}, *zabbix.API) (string, error)
 type getParentFunc func(*zabbix.API, string) (string, error)
 
-func deleteRetry(id string, get getParentFunc, delete deleteFunc, api *zabbix.API) error {
-	return resource.Retry(time.Minute, func() *resource.RetryError {
+// ignoreNotFound treat the deletion of an object which is already gone as a success
+func ignoreNotFound(err error, kind string, id string) error {
+	if isNotFoundError(err) {
+		log.Printf("[DEBUG] %s with id %s doesn't exist anymore", kind, id)
+		return nil
+	}
+	return err
+}
+
+// apiRetry call f until it succeeds, fails with a non transient error or timeout expires
+func apiRetry(timeout time.Duration, f func() error) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if sqlError(err) {
+			log.Printf("[DEBUG] Zabbix API call failed with a transient error, will retry: %s", err.Error())
+			return resource.RetryableError(err)
+		}
+		return resource.NonRetryableError(err)
+	})
+}
+
+func deleteRetry(id string, get getParentFunc, delete deleteFunc, api *zabbix.API, timeout time.Duration) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
 		parentID, err := get(api, id)
 		if err != nil {
+			if isNotFoundError(err) {
+				log.Printf("[DEBUG] Object with id %s doesn't exist anymore", id)
+				return nil
+			}
 			if sqlError(err) {
 				return resource.RetryableError(err)
 			}
@@ -49,18 +96,27 @@ func deleteRetry(id string, get getParentFunc, delete deleteFunc, api *zabbix.AP
 				return resource.NonRetryableError(fmt.Errorf("Expected to delete %d object and %d were deleted", nbExpected, len(deleteIDs)))
 			}
 			return nil
+		} else if isNotFoundError(err) {
+			log.Printf("[DEBUG] Object with id %s doesn't exist anymore", id)
+			return nil
 		} else if sqlError(err) {
 			log.Printf("[DEBUG] Deletion failed. Got error %s, with id %s", err.Error(), id)
-			return resource.RetryableError(fmt.Errorf("Failed to delete object with id: %s, got error %s", id, err.Error()))
+			return resource.RetryableError(describeAPIError(err, "Failed to delete object with id %s", id))
 		} else {
-			return resource.NonRetryableError(err)
+			return resource.NonRetryableError(describeAPIError(err, "Failed to delete object with id %s", id))
 		}
 	})
 }
 
 func createRetry(d *schema.ResourceData, meta interface{}, create createFunc, createArg interface{}, read schema.ReadFunc) error {
-	return resource.Retry(time.Minute, func() *resource.RetryError {
-		api := meta.(*zabbix.API)
+	// createRetry is also used to update existing objects
+	timeout := d.Timeout(schema.TimeoutCreate)
+	if d.Id() != "" {
+		timeout = d.Timeout(schema.TimeoutUpdate)
+	}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		api := meta.(*providerMeta).api
 		id, err := create(createArg, api)
 		if err != nil {
 			if sqlError(err) {
@@ -147,3 +203,69 @@ func createZabbixHttpTestHeaders(d *schema.ResourceData) zabbix.Headers {
 	}
 	return headers
 }
+
+// readError remove the resource from state when err reports the object doesn't
+// exist anymore, so terraform plans to create it again. Other errors are
+// returned prefixed with what was read
+func readError(d *schema.ResourceData, err error, format string, a ...interface{}) error {
+	if isNotFoundError(err) {
+		log.Printf("[WARN] %s: %s, removing it from state", fmt.Sprintf(format, a...), err)
+		d.SetId("")
+		return nil
+	}
+	return describeAPIError(err, format, a...)
+}
+
+// lookupObjects return the field of the objects of a get method whose byField
+// is one of values, keyed by value. A not found error is returned for the
+// values not matching any object
+func lookupObjects(api *zabbix.API, method, byField, field string, values []string) (map[string]string, error) {
+	var objects []map[string]interface{}
+
+	err := api.CallWithErrorParse(method, zabbix.Params{
+		"output": []string{byField, field},
+		"filter": map[string]interface{}{
+			byField: values,
+		},
+	}, &objects)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]string, len(objects))
+	for _, o := range objects {
+		found[fmt.Sprintf("%v", o[byField])] = fmt.Sprintf("%v", o[field])
+	}
+
+	for _, value := range values {
+		if _, ok := found[value]; !ok {
+			return nil, newNotFoundError("%s %q doesnt exist in zabbix server", strings.TrimSuffix(method, ".get"), value)
+		}
+	}
+	return found, nil
+}
+
+// importByIDOrName is an importer accepting the numeric id of the object, or its
+// name prefixed by "name:" resolved through the get method of the API
+func importByIDOrName(kind, method, idField, nameField string) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		api := meta.(*providerMeta).api
+
+		if !strings.HasPrefix(d.Id(), "name:") {
+			if _, err := strconv.ParseUint(d.Id(), 10, 64); err != nil {
+				return nil, fmt.Errorf("Expected a numeric %s id or name:<name>, got %q", kind, d.Id())
+			}
+			return []*schema.ResourceData{d}, nil
+		}
+
+		name := strings.TrimPrefix(d.Id(), "name:")
+
+		ids, err := lookupObjects(api, method, nameField, idField, []string{name})
+		if err != nil {
+			return nil, describeAPIError(err, "Failed to look up %s %s", kind, name)
+		}
+
+		d.SetId(ids[name])
+		return []*schema.ResourceData{d}, nil
+	}
+}
@@ -0,0 +1,228 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// HostAvailabilityStates zabbix different availability states of interfaces
+var HostAvailabilityStates = map[string]string{
+	"0": "unknown",
+	"1": "available",
+	"2": "unavailable",
+}
+
+// hostAvailabilityPrefixes are the prefixes of the availability fields of each
+// interface type on hosts before Zabbix 5.4
+var hostAvailabilityPrefixes = map[string]string{
+	"agent": "",
+	"snmp":  "snmp_",
+	"ipmi":  "ipmi_",
+	"jmx":   "jmx_",
+}
+
+var waitForAvailabilitySchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"interface_types": &schema.Schema{
+			Type: schema.TypeSet,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if _, ok := HostInterfaceTypes[val.(string)]; !ok {
+						errs = append(errs, fmt.Errorf("%q must be one of agent, snmp, ipmi or jmx, got %q", key, val))
+					}
+					return
+				},
+			},
+			Optional:    true,
+			Description: "Types of the interfaces to wait for, all the types of the interfaces of the host when empty.",
+		},
+		"timeout": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "5m",
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				if _, err := time.ParseDuration(val.(string)); err != nil {
+					errs = append(errs, fmt.Errorf("%q must be a duration such as 90s or 5m, got %q", key, val))
+				}
+				return
+			},
+			Description: "Time to wait for the interfaces to become available.",
+		},
+	},
+}
+
+// interfaceAvailability is the availability of the main interface of a type
+type interfaceAvailability struct {
+	State string
+	Error string
+}
+
+// getHostAvailability return the availability of the main interface of each
+// type of the host, reported by the interfaces since Zabbix 5.4 and by the
+// host before
+func getHostAvailability(m *providerMeta, hostID string) (map[string]interfaceAvailability, error) {
+	availability := make(map[string]interfaceAvailability)
+
+	if m.supports(capabilityInterfaceAvailability) {
+		var interfaces []struct {
+			Type      string `json:"type"`
+			Main      string `json:"main"`
+			Available string `json:"available"`
+			Error     string `json:"error"`
+		}
+		err := m.api.CallWithErrorParse("hostinterface.get", zabbix.Params{
+			"output":  []string{"type", "main", "available", "error"},
+			"hostids": hostID,
+		}, &interfaces)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range interfaces {
+			if i.Main == "1" {
+				availability[interfaceTypeName(i.Type)] = interfaceAvailability{
+					State: HostAvailabilityStates[i.Available],
+					Error: i.Error,
+				}
+			}
+		}
+		return availability, nil
+	}
+
+	output := []string{"hostid"}
+	for _, prefix := range hostAvailabilityPrefixes {
+		output = append(output, prefix+"available", prefix+"error")
+	}
+
+	var hosts []map[string]interface{}
+	err := m.api.CallWithErrorParse("host.get", zabbix.Params{
+		"output":           output,
+		"selectInterfaces": []string{"type"},
+		"hostids":          hostID,
+	}, &hosts)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) != 1 {
+		return nil, newNotFoundError("Host %s doesn't exist", hostID)
+	}
+
+	field := func(name string) string {
+		if value, ok := hosts[0][name]; ok && value != nil {
+			return fmt.Sprint(value)
+		}
+		return ""
+	}
+
+	interfaces, _ := hosts[0]["interfaces"].([]interface{})
+	for _, i := range interfaces {
+		typeName := interfaceTypeName(fmt.Sprint(i.(map[string]interface{})["type"]))
+		prefix := hostAvailabilityPrefixes[typeName]
+		availability[typeName] = interfaceAvailability{
+			State: HostAvailabilityStates[field(prefix+"available")],
+			Error: field(prefix + "error"),
+		}
+	}
+	return availability, nil
+}
+
+// setHostAvailability set the availability and availability_error attributes
+func setHostAvailability(d *schema.ResourceData, availability map[string]interfaceAvailability) {
+	states := make(map[string]interface{}, len(availability))
+	var errs []string
+	for typeName, a := range availability {
+		states[typeName] = a.State
+		if a.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", typeName, a.Error))
+		}
+	}
+	sort.Strings(errs)
+
+	d.Set("availability", states)
+	d.Set("availability_error", strings.Join(errs, "; "))
+}
+
+// waitForHostAvailability wait for the interfaces set in wait_for_availability
+// to become available, it fails with their state and error once the timeout expires
+func waitForHostAvailability(d *schema.ResourceData, m *providerMeta) error {
+	blocks := d.Get("wait_for_availability").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	config := blocks[0].(map[string]interface{})
+
+	timeout, err := time.ParseDuration(config["timeout"].(string))
+	if err != nil {
+		return err
+	}
+
+	types := []string{}
+	for _, t := range config["interface_types"].(*schema.Set).List() {
+		types = append(types, t.(string))
+	}
+	if len(types) == 0 {
+		seen := make(map[string]bool)
+		for _, i := range d.Get("interfaces").([]interface{}) {
+			typeName := i.(map[string]interface{})["type"].(string)
+			if !seen[typeName] {
+				seen[typeName] = true
+				types = append(types, typeName)
+			}
+		}
+	}
+	sort.Strings(types)
+
+	var availability map[string]interfaceAvailability
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		var err error
+		availability, err = getHostAvailability(m, d.Id())
+		if err != nil {
+			return resource.NonRetryableError(describeAPIError(err, "Failed to read availability of host %s", d.Id()))
+		}
+
+		for _, t := range types {
+			a, ok := availability[t]
+			if !ok {
+				return resource.NonRetryableError(fmt.Errorf("Host %s has no %s interface to wait for", d.Id(), t))
+			}
+			if a.State != "available" {
+				return resource.RetryableError(fmt.Errorf("%s interface is %s", t, a.State))
+			}
+		}
+		return nil
+	})
+
+	if availability != nil {
+		setHostAvailability(d, availability)
+	}
+	if err == nil {
+		return nil
+	}
+	if availability == nil {
+		return err
+	}
+
+	var states []string
+	for _, t := range types {
+		a, ok := availability[t]
+		if !ok || a.State == "available" {
+			continue
+		}
+		state := fmt.Sprintf("%s is %s", t, a.State)
+		if a.Error != "" {
+			state += fmt.Sprintf(" (%s)", a.Error)
+		}
+		states = append(states, state)
+	}
+	if len(states) == 0 {
+		return err
+	}
+	return fmt.Errorf("Interfaces of host %s didn't become available within %s: %s", d.Id(), timeout, strings.Join(states, ", "))
+}
@@ -0,0 +1,162 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// HostInventoryModes zabbix different inventory modes
+var HostInventoryModes = map[string]string{
+	"disabled":  "-1",
+	"manual":    "0",
+	"automatic": "1",
+}
+
+// hostInventoryFields are the fields of the host inventory, in the order of the
+// inventory_link of the items populating them
+var hostInventoryFields = []string{
+	"type", "type_full", "name", "alias", "os", "os_full", "os_short",
+	"serialno_a", "serialno_b", "tag", "asset_tag", "macaddress_a", "macaddress_b",
+	"hardware", "hardware_full", "software", "software_full",
+	"software_app_a", "software_app_b", "software_app_c", "software_app_d", "software_app_e",
+	"contact", "location", "location_lat", "location_lon", "notes",
+	"chassis", "model", "hw_arch", "vendor", "contract_number", "installer_name", "deployment_status",
+	"url_a", "url_b", "url_c",
+	"host_networks", "host_netmask", "host_router", "oob_ip", "oob_netmask", "oob_router",
+	"date_hw_purchase", "date_hw_install", "date_hw_expiry", "date_hw_decomm",
+	"site_address_a", "site_address_b", "site_address_c", "site_city", "site_state",
+	"site_country", "site_zip", "site_rack", "site_notes",
+	"poc_1_name", "poc_1_email", "poc_1_phone_a", "poc_1_phone_b", "poc_1_cell", "poc_1_screen", "poc_1_notes",
+	"poc_2_name", "poc_2_email", "poc_2_phone_a", "poc_2_phone_b", "poc_2_cell", "poc_2_screen", "poc_2_notes",
+}
+
+// hostInventory is the inventory of a host, the API returns an empty array
+// instead of an object when the inventory is disabled
+type hostInventory map[string]string
+
+func (inventory *hostInventory) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		*inventory = nil
+		return nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*inventory = make(hostInventory, len(values))
+	for key, value := range values {
+		if value != nil {
+			(*inventory)[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+func isHostInventoryField(name string) bool {
+	for _, field := range hostInventoryFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateHostInventoryFields(val interface{}, key string) (warns []string, errs []error) {
+	for name := range val.(map[string]interface{}) {
+		if !isHostInventoryField(name) {
+			errs = append(errs, fmt.Errorf("%q contains an unknown inventory field %q, expected one of: %s", key, name, strings.Join(hostInventoryFields, ", ")))
+		}
+	}
+	return
+}
+
+func hostInventoryModeName(mode string) string {
+	for name, id := range HostInventoryModes {
+		if id == mode {
+			return name
+		}
+	}
+	return mode
+}
+
+func createHostInventory(d *schema.ResourceData) hostInventory {
+	inventory := hostInventory{}
+	for name, value := range d.Get("inventory").(map[string]interface{}) {
+		inventory[name] = value.(string)
+	}
+
+	// fields removed from the configuration are emptied
+	if d.HasChange("inventory") {
+		previous, _ := d.GetChange("inventory")
+		for name := range previous.(map[string]interface{}) {
+			if _, ok := inventory[name]; !ok {
+				inventory[name] = ""
+			}
+		}
+	}
+
+	if len(inventory) == 0 {
+		return nil
+	}
+	return inventory
+}
+
+// getItemInventoryFields return the inventory fields populated by the items of a host
+func getItemInventoryFields(api *zabbix.API, hostID string) (map[string]bool, error) {
+	var items []struct {
+		InventoryLink string `json:"inventory_link"`
+	}
+
+	err := api.CallWithErrorParse("item.get", zabbix.Params{
+		"output":  []string{"inventory_link"},
+		"hostids": hostID,
+	}, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool)
+	for _, item := range items {
+		link := atoiOrZero(item.InventoryLink)
+		if link > 0 && link <= len(hostInventoryFields) {
+			fields[hostInventoryFields[link-1]] = true
+		}
+	}
+	return fields, nil
+}
+
+// flattenHostInventory return the inventory fields to keep in state. In automatic
+// mode, the fields populated by items keep the configured value and the ones
+// missing from the configuration are ignored
+func flattenHostInventory(inventory hostInventory, configured map[string]interface{}, mode string, itemFields map[string]bool) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for name, value := range inventory {
+		if !isHostInventoryField(name) {
+			continue
+		}
+
+		configuredValue, isConfigured := configured[name]
+		if mode == HostInventoryModes["automatic"] {
+			if !isConfigured {
+				continue
+			}
+			if itemFields[name] {
+				values[name] = configuredValue
+				continue
+			}
+		}
+
+		if value != "" || isConfigured {
+			values[name] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,41 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHostInventoryUnmarshal(t *testing.T) {
+	var settings []hostSettings
+	body := `[{"inventory_mode":"-1","inventory":[]},{"inventory_mode":"0","inventory":{"hostid":"10084","serialno_a":"ABC123","location":""}}]`
+	if err := json.Unmarshal([]byte(body), &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings[0].Inventory != nil {
+		t.Fatalf("Got inventory %v, expected none", settings[0].Inventory)
+	}
+	if settings[1].Inventory["serialno_a"] != "ABC123" {
+		t.Fatalf("Got serialno_a %q, expected ABC123", settings[1].Inventory["serialno_a"])
+	}
+}
+
+func TestFlattenHostInventory(t *testing.T) {
+	inventory := hostInventory{
+		"hostid":     "10084",
+		"serialno_a": "ABC123",
+		"location":   "",
+		"os":         "Linux 5.10",
+		"contact":    "ops",
+	}
+
+	manual := flattenHostInventory(inventory, map[string]interface{}{"location": ""}, HostInventoryModes["manual"], nil)
+	if len(manual) != 4 || manual["serialno_a"] != "ABC123" || manual["location"] != "" {
+		t.Fatalf("Got manual inventory %v", manual)
+	}
+
+	configured := map[string]interface{}{"os": "Linux", "contact": "ops"}
+	automatic := flattenHostInventory(inventory, configured, HostInventoryModes["automatic"], map[string]bool{"os": true})
+	if len(automatic) != 2 || automatic["os"] != "Linux" || automatic["contact"] != "ops" {
+		t.Fatalf("Got automatic inventory %v", automatic)
+	}
+}
@@ -0,0 +1,201 @@
+package zabbix
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// HostMonitoredBy zabbix different values of monitored_by since Zabbix 7.0
+var HostMonitoredBy = map[string]string{
+	"server":      "0",
+	"proxy":       "1",
+	"proxy_group": "2",
+}
+
+var monitoredByKeys = []string{
+	"monitored_by.0.proxy",
+	"monitored_by.0.proxy_id",
+	"monitored_by.0.proxy_group",
+	"monitored_by.0.proxy_group_id",
+}
+
+var monitoredBySchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"proxy": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: monitoredByKeys,
+			Description:  "Name of the proxy monitoring the host.",
+		},
+		"proxy_id": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: monitoredByKeys,
+			Description:  "ID of the proxy monitoring the host.",
+		},
+		"proxy_group": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: monitoredByKeys,
+			Description:  "Name of the proxy group monitoring the host (Zabbix >=7.0).",
+		},
+		"proxy_group_id": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: monitoredByKeys,
+			Description:  "ID of the proxy group monitoring the host (Zabbix >=7.0).",
+		},
+	},
+}
+
+// hostMonitoring is what monitors a host: the server, a proxy or a proxy group
+type hostMonitoring struct {
+	kind string
+	id   string
+}
+
+// proxyNameField is the field of proxy.get holding the name of the proxy,
+// renamed in Zabbix 7.0
+func proxyNameField(m *providerMeta) string {
+	if m.supports(capabilityProxyGroups) {
+		return "name"
+	}
+	return "host"
+}
+
+// expandHostMonitoring resolve the monitored_by block, or the deprecated
+// proxy_hostid when it is unset
+func expandHostMonitoring(d *schema.ResourceData, m *providerMeta) (hostMonitoring, error) {
+	blocks := d.Get("monitored_by").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		if id := d.Get("proxy_hostid").(string); id != "" && id != "0" {
+			return hostMonitoring{kind: "proxy", id: id}, nil
+		}
+		return hostMonitoring{kind: "server"}, nil
+	}
+	config := blocks[0].(map[string]interface{})
+
+	if id := config["proxy_id"].(string); id != "" {
+		return hostMonitoring{kind: "proxy", id: id}, nil
+	}
+	if id := config["proxy_group_id"].(string); id != "" {
+		return hostMonitoring{kind: "proxy_group", id: id}, nil
+	}
+	if name := config["proxy"].(string); name != "" {
+		ids, err := lookupObjects(m.api, "proxy.get", proxyNameField(m), "proxyid", []string{name})
+		if err != nil {
+			return hostMonitoring{}, err
+		}
+		return hostMonitoring{kind: "proxy", id: ids[name]}, nil
+	}
+	if name := config["proxy_group"].(string); name != "" {
+		ids, err := lookupObjects(m.api, "proxygroup.get", "name", "proxy_groupid", []string{name})
+		if err != nil {
+			return hostMonitoring{}, err
+		}
+		return hostMonitoring{kind: "proxy_group", id: ids[name]}, nil
+	}
+	return hostMonitoring{kind: "server"}, nil
+}
+
+// apply set the fields of the host monitoring, monitored_by and proxyid or
+// proxy_groupid since Zabbix 7.0 and proxy_hostid before
+func (monitoring hostMonitoring) apply(host *hostObject, m *providerMeta) error {
+	if !m.supports(capabilityProxyGroups) {
+		switch monitoring.kind {
+		case "proxy":
+			host.ProxyHostID = monitoring.id
+		case "proxy_group":
+			return fmt.Errorf("Proxy groups can't be used with Zabbix Server %s, they are available %s", m.serverVersion, capabilityVersionRange(capabilityProxyGroups))
+		default:
+			host.ProxyHostID = "0"
+		}
+		return nil
+	}
+
+	host.MonitoredBy = HostMonitoredBy[monitoring.kind]
+	switch monitoring.kind {
+	case "proxy":
+		host.ProxyID = monitoring.id
+	case "proxy_group":
+		host.ProxyGroupID = monitoring.id
+	}
+	return nil
+}
+
+// hostMonitoringOf return the monitoring of a host read from the server
+func hostMonitoringOf(m *providerMeta, proxyHostID string, settings *hostSettings) hostMonitoring {
+	if !m.supports(capabilityProxyGroups) {
+		if proxyHostID != "" && proxyHostID != "0" {
+			return hostMonitoring{kind: "proxy", id: proxyHostID}
+		}
+		return hostMonitoring{kind: "server"}
+	}
+
+	switch settings.MonitoredBy {
+	case HostMonitoredBy["proxy"]:
+		return hostMonitoring{kind: "proxy", id: settings.ProxyID}
+	case HostMonitoredBy["proxy_group"]:
+		return hostMonitoring{kind: "proxy_group", id: settings.ProxyGroupID}
+	}
+	return hostMonitoring{kind: "server"}
+}
+
+// readHostMonitoring set monitored_by in the form of the state, by name or by
+// id, and proxy_hostid for the hosts still using it
+func readHostMonitoring(d *schema.ResourceData, m *providerMeta, monitoring hostMonitoring) error {
+	d.Set("monitored_by_type", monitoring.kind)
+
+	var state map[string]interface{}
+	if blocks := d.Get("monitored_by").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		state = blocks[0].(map[string]interface{})
+	}
+
+	if state == nil && monitoring.kind != "proxy_group" {
+		d.Set("proxy_hostid", "0")
+		if monitoring.kind == "proxy" {
+			d.Set("proxy_hostid", monitoring.id)
+		}
+		d.Set("monitored_by", nil)
+		return nil
+	}
+
+	d.Set("proxy_hostid", "0")
+
+	block := map[string]interface{}{
+		"proxy":          "",
+		"proxy_id":       "",
+		"proxy_group":    "",
+		"proxy_group_id": "",
+	}
+
+	switch monitoring.kind {
+	case "proxy":
+		if state != nil && state["proxy"].(string) != "" {
+			names, err := lookupObjects(m.api, "proxy.get", "proxyid", proxyNameField(m), []string{monitoring.id})
+			if err != nil {
+				return err
+			}
+			block["proxy"] = names[monitoring.id]
+		} else {
+			block["proxy_id"] = monitoring.id
+		}
+	case "proxy_group":
+		if state != nil && state["proxy_group"].(string) != "" {
+			names, err := lookupObjects(m.api, "proxygroup.get", "proxy_groupid", "name", []string{monitoring.id})
+			if err != nil {
+				return err
+			}
+			block["proxy_group"] = names[monitoring.id]
+		} else {
+			block["proxy_group_id"] = monitoring.id
+		}
+	default:
+		d.Set("monitored_by", nil)
+		return nil
+	}
+
+	d.Set("monitored_by", []interface{}{block})
+	return nil
+}
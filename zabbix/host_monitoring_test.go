@@ -0,0 +1,66 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHostMonitoringApply(t *testing.T) {
+	cases := []struct {
+		serverVersion string
+		monitoring    hostMonitoring
+		expected      string
+	}{
+		{"6.0.0", hostMonitoring{kind: "server"}, `{"proxy_hostid":"0"}`},
+		{"6.0.0", hostMonitoring{kind: "proxy", id: "10"}, `{"proxy_hostid":"10"}`},
+		{"7.0.0", hostMonitoring{kind: "server"}, `{"monitored_by":"0"}`},
+		{"7.0.0", hostMonitoring{kind: "proxy", id: "10"}, `{"monitored_by":"1","proxyid":"10"}`},
+		{"7.0.0", hostMonitoring{kind: "proxy_group", id: "3"}, `{"monitored_by":"2","proxy_groupid":"3"}`},
+	}
+
+	for _, c := range cases {
+		var host hostObject
+		if err := c.monitoring.apply(&host, &providerMeta{serverVersion: c.serverVersion}); err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+
+		fields := map[string]interface{}{
+			"proxy_hostid":  host.ProxyHostID,
+			"monitored_by":  host.MonitoredBy,
+			"proxyid":       host.ProxyID,
+			"proxy_groupid": host.ProxyGroupID,
+		}
+		for name, value := range fields {
+			if value == "" {
+				delete(fields, name)
+			}
+		}
+		got, _ := json.Marshal(fields)
+		if string(got) != c.expected {
+			t.Errorf("Got %s for %v on %s, expected %s", got, c.monitoring, c.serverVersion, c.expected)
+		}
+	}
+
+	var host hostObject
+	if err := (hostMonitoring{kind: "proxy_group", id: "3"}).apply(&host, &providerMeta{serverVersion: "6.4.0"}); err == nil {
+		t.Errorf("Expected proxy groups to be rejected before Zabbix 7.0")
+	}
+}
+
+func TestHostMonitoringOf(t *testing.T) {
+	legacy := &providerMeta{serverVersion: "6.4.0"}
+	if m := hostMonitoringOf(legacy, "0", &hostSettings{}); m.kind != "server" {
+		t.Errorf("Got %v, expected server", m)
+	}
+	if m := hostMonitoringOf(legacy, "10", &hostSettings{}); m.kind != "proxy" || m.id != "10" {
+		t.Errorf("Got %v, expected proxy 10", m)
+	}
+
+	current := &providerMeta{serverVersion: "7.0.1"}
+	if m := hostMonitoringOf(current, "", &hostSettings{MonitoredBy: "2", ProxyGroupID: "3"}); m.kind != "proxy_group" || m.id != "3" {
+		t.Errorf("Got %v, expected proxy group 3", m)
+	}
+	if m := hostMonitoringOf(current, "", &hostSettings{MonitoredBy: "0"}); m.kind != "server" {
+		t.Errorf("Got %v, expected server", m)
+	}
+}
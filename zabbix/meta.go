@@ -0,0 +1,124 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/mcuadros/go-version"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// providerMeta is the meta shared by the resources of a provider instance
+type providerMeta struct {
+	api *zabbix.API
+
+	// serverVersion is fetched once when the provider is configured, or pinned
+	// with the server_version argument
+	serverVersion string
+}
+
+// capability is a feature of the zabbix API only available on some server versions
+type capability int
+
+const (
+	capabilityTimeUnits capability = iota
+	capabilityTriggerTags
+	capabilityHostTags
+	capabilityInterfaceDetails
+	capabilityItemTags
+	capabilityItemPreprocessing
+	capabilityLLDPreprocessing
+	capabilityItemDataTypeDelta
+	capabilityNewExpressionSyntax
+	capabilityAPIToken
+	capabilityReadableTLSPSK
+	capabilityTemplateGroups
+	capabilityProxyGroups
+	capabilityHostPrototypeMacros
+	capabilityHostPrototypeInterfaces
+	capabilityInterfaceAvailability
+	capabilityHostPrototypeTags
+	capabilityInventoryModeField
+	capabilityLoginUsername
+)
+
+// capabilityVersions are the server versions introducing (since) and
+// removing (until) each capability, empty when there is no bound
+var capabilityVersions = map[capability]struct {
+	name  string
+	since string
+	until string
+}{
+	capabilityTimeUnits:               {name: "time suffixes", since: "3.4.0"},
+	capabilityTriggerTags:             {name: "trigger tags", since: "3.2.0"},
+	capabilityHostTags:                {name: "host tags", since: "4.2.0"},
+	capabilityInterfaceDetails:        {name: "SNMP interface details", since: "5.0.0"},
+	capabilityItemTags:                {name: "item tags", since: "5.4.0"},
+	capabilityItemPreprocessing:       {name: "item preprocessing", since: "4.0.0"},
+	capabilityLLDPreprocessing:        {name: "LLD rule preprocessing and macro paths", since: "4.2.0"},
+	capabilityItemDataTypeDelta:       {name: "item data_type and delta", until: "3.4.0"},
+	capabilityNewExpressionSyntax:     {name: "new trigger expression syntax", since: "5.4.0"},
+	capabilityAPIToken:                {name: "API tokens", since: "5.4.0"},
+	capabilityReadableTLSPSK:          {name: "readable PSK", until: "5.4.0"},
+	capabilityTemplateGroups:          {name: "template groups", since: "6.2.0"},
+	capabilityProxyGroups:             {name: "proxy groups", since: "7.0.0"},
+	capabilityHostPrototypeMacros:     {name: "host prototype macros", since: "5.0.0"},
+	capabilityHostPrototypeInterfaces: {name: "host prototype custom interfaces", since: "5.2.0"},
+	capabilityInterfaceAvailability:   {name: "availability of interfaces", since: "5.4.0"},
+	capabilityHostPrototypeTags:       {name: "host prototype tags", since: "5.4.0"},
+	capabilityInventoryModeField:      {name: "inventory_mode field", since: "4.4.0"},
+	capabilityLoginUsername:           {name: "username parameter of user.login", since: "5.4.0"},
+}
+
+func (c capability) String() string {
+	return capabilityVersions[c].name
+}
+
+// supports tell if the zabbix server of the provider instance has capability c
+func (m *providerMeta) supports(c capability) bool {
+	return isCapabilitySupported(m.serverVersion, c)
+}
+
+func isCapabilitySupported(serverVersion string, c capability) bool {
+	bounds := capabilityVersions[c]
+	if bounds.since != "" && !version.Compare(serverVersion, bounds.since, ">=") {
+		return false
+	}
+	if bounds.until != "" && !version.Compare(serverVersion, bounds.until, "<") {
+		return false
+	}
+	return true
+}
+
+// capabilityVersionRange describe the server versions having capability c
+func capabilityVersionRange(c capability) string {
+	bounds := capabilityVersions[c]
+	if bounds.until != "" {
+		return fmt.Sprintf("before %s", bounds.until)
+	}
+	return fmt.Sprintf("since %s", bounds.since)
+}
+
+// validateCapabilities is a CustomizeDiff rejecting at plan time the attributes
+// set in the configuration whose capability the zabbix server doesn't have
+func validateCapabilities(attributes map[string]capability) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		m := meta.(*providerMeta)
+
+		keys := make([]string, 0, len(attributes))
+		for key := range attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			c := attributes[key]
+			if _, ok := d.GetOk(key); !ok || m.supports(c) {
+				continue
+			}
+			return fmt.Errorf("%q can't be used with Zabbix Server %s, %s are available %s", key, m.serverVersion, c, capabilityVersionRange(c))
+		}
+		return nil
+	}
+}
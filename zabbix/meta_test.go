@@ -0,0 +1,36 @@
+package zabbix
+
+import "testing"
+
+func TestCapabilitySupported(t *testing.T) {
+	cases := []struct {
+		version    string
+		capability capability
+		expected   bool
+	}{
+		{"3.2.0", capabilityTimeUnits, false},
+		{"3.4.0", capabilityTimeUnits, true},
+		{"5.0.3", capabilityNewExpressionSyntax, false},
+		{"5.4", capabilityNewExpressionSyntax, true},
+		{"3.2.11", capabilityItemDataTypeDelta, true},
+		{"3.4.0", capabilityItemDataTypeDelta, false},
+		{"6.0.0", capabilityTemplateGroups, false},
+		{"6.2.1", capabilityTemplateGroups, true},
+	}
+
+	for _, c := range cases {
+		meta := &providerMeta{serverVersion: c.version}
+		if supported := meta.supports(c.capability); supported != c.expected {
+			t.Errorf("%s on %s: got %t, expected %t", c.capability, c.version, supported, c.expected)
+		}
+	}
+}
+
+func TestCapabilityVersionRange(t *testing.T) {
+	if r := capabilityVersionRange(capabilityItemTags); r != "since 5.4.0" {
+		t.Errorf("Got %q, expected since 5.4.0", r)
+	}
+	if r := capabilityVersionRange(capabilityItemDataTypeDelta); r != "before 3.4.0" {
+		t.Errorf("Got %q, expected before 3.4.0", r)
+	}
+}
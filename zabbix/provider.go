@@ -1,17 +1,19 @@
 package zabbix
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/mcuadros/go-version"
 	"github.com/nzolot/go-zabbix-api"
 )
 
+var serverVersionRegexp = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
 // Provider define the provider and his resources
 func Provider() terraform.ResourceProvider {
 	p := &schema.Provider{
@@ -30,6 +32,13 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_PASSWORD", nil),
 				Default:     "undef",
 			},
+			"api_token": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_API_TOKEN", nil),
+				Description: "API token used instead of user and password (Zabbix >=5.4).",
+			},
 			"server_url": &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    false,
@@ -37,11 +46,134 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_SERVER_URL", nil),
 				Default:     "http://zabbix.nikospace.net/api_mock.php",
 			},
+			"server_version": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_SERVER_VERSION", ""),
+				Description: "Version of the Zabbix server, e.g. `5.4.0`. Skips the version lookup of the API and delays the login to the first API call when set.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(string)
+					if v != "" && !serverVersionRegexp.MatchString(v) {
+						errs = append(errs, fmt.Errorf("%q must be a version like 5.4.0, got: %s", key, v))
+					}
+					return
+				},
+			},
+			"ca_file": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("ZABBIX_CA_FILE", ""),
+				ConflictsWith: []string{"ca_pem"},
+				Description:   "Path to a PEM encoded CA bundle used to verify the Zabbix frontend certificate.",
+			},
+			"ca_pem": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_file"},
+				Description:   "PEM encoded CA bundle used to verify the Zabbix frontend certificate.",
+			},
+			"insecure_skip_verify": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_INSECURE_SKIP_VERIFY", false),
+				Description: "Skip the TLS certificate verification of the Zabbix frontend.",
+			},
+			"client_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_CLIENT_CERT_FILE", ""),
+				Description: "Path to a PEM encoded client certificate for mutual TLS.",
+			},
+			"client_key_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_CLIENT_KEY_FILE", ""),
+				Description: "Path to the PEM encoded private key of client_cert_file.",
+			},
+			"request_timeout": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Timeout in seconds of a Zabbix API call including its retries, 0 means no timeout.",
+			},
+			"proxy_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ZABBIX_PROXY_URL", ""),
+				Description: "HTTP(S) proxy used to reach the Zabbix API, defaults to the HTTP_PROXY/HTTPS_PROXY environment.",
+			},
+			"max_retries": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     3,
+				Description: "Maximum number of retries of an API call failing with a transient network or HTTP error. Calls changing objects are only retried when the server didn't receive them.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < 0 {
+						errs = append(errs, fmt.Errorf("%q, must be greater or equal to 0, got %d", key, v))
+					}
+					return
+				},
+			},
+			"retry_backoff": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "Base delay in seconds between retries, doubled on every attempt with a random jitter.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < 0 {
+						errs = append(errs, fmt.Errorf("%q, must be greater or equal to 0, got %d", key, v))
+					}
+					return
+				},
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum number of API calls running at the same time, 0 means unlimited.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < 0 {
+						errs = append(errs, fmt.Errorf("%q, must be greater or equal to 0, got %d", key, v))
+					}
+					return
+				},
+			},
+			"requests_per_second": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Default:     0.0,
+				Description: "Maximum number of API calls sent per second, 0 means unlimited.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(float64)
+					if v < 0 {
+						errs = append(errs, fmt.Errorf("%q, must be greater or equal to 0, got %f", key, v))
+					}
+					return
+				},
+			},
+			"http_headers": &schema.Schema{
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Additional HTTP headers sent with every Zabbix API request.",
+			},
+			"basic_auth": &schema.Schema{
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Elem:        basicAuthSchema,
+				Description: "HTTP basic auth credentials for a reverse proxy in front of the Zabbix API.",
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"zabbix_server": dataSourceZabbixServer(),
 			"zabbix_host":   dataSourceZabbixHost(),
+			"zabbix_hosts":  dataSourceZabbixHosts(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -54,6 +186,7 @@ func Provider() terraform.ResourceProvider {
 			"zabbix_lld_rule":          resourceZabbixLLDRule(),
 			"zabbix_item_prototype":    resourceZabbixItemPrototype(),
 			"zabbix_trigger_prototype": resourceZabbixTriggerPrototype(),
+			"zabbix_host_prototype":    resourceZabbixHostPrototype(),
 			"zabbix_web_check":         resourceZabbixHttpTest(),
 			"zabbix_proxy":             resourceZabbixProxy(),
 		},
@@ -77,33 +210,82 @@ func providerConfigure(d *schema.ResourceData, terraformVersion string) (interfa
 
 	api.UserAgent = fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
 
-	if logging.IsDebugOrHigher() {
-		httpClient := http.Client{}
-		httpClient.Transport = logging.NewTransport("Zabbix", http.DefaultTransport)
-		api.SetClient(&httpClient)
+	httpClient, err := createHTTPClient(d)
+	if err != nil {
+		return nil, err
+	}
+	api.SetClient(httpClient)
+
+	serverVersion := d.Get("server_version").(string)
+	if serverVersion == "" {
+		serverVersion, err = api.Version()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get Zabbix Server version, set server_version to skip the lookup: %v", err)
+		}
 	}
+	log.Printf("[DEBUG] Zabbix Server version is %s\n", serverVersion)
 
-	if _, err := api.Login(d.Get("user").(string), d.Get("password").(string)); err != nil {
+	meta := &providerMeta{
+		api:           api,
+		serverVersion: serverVersion,
+	}
+
+	if err := providerLogin(d, meta, httpClient); err != nil {
 		return nil, err
 	}
 
-	return api, nil
+	return meta, nil
 }
 
-func getZabbixServerVersion(meta interface{}) string {
-	api := meta.(*zabbix.API)
-	v, err := api.Version()
-	if err != nil {
-		log.Printf("[WARN] Failed to get Zabbix Server version: %v\n", err)
-		return ""
+// providerLogin authenticates the API client either with an API token or
+// with user and password through user.login. With server_version set, the
+// user.login is deferred to the first API call so configuring the provider
+// doesn't need the server
+func providerLogin(d *schema.ResourceData, meta *providerMeta, httpClient *http.Client) error {
+	api := meta.api
+	user := d.Get("user").(string)
+	password := d.Get("password").(string)
+	token := d.Get("api_token").(string)
+
+	if token == "" {
+		session, ok := httpClient.Transport.(*sessionTransport)
+		if ok && d.Get("server_version").(string) != "" {
+			// the user parameter of user.login was renamed username in Zabbix 5.4
+			usernameField := "user"
+			if meta.supports(capabilityLoginUsername) {
+				usernameField = "username"
+			}
+			return session.deferLogin(usernameField, user, password)
+		}
+		_, err := api.Login(user, password)
+		return err
+	}
+
+	if isProviderCredentialSet(user) || isProviderCredentialSet(password) {
+		return errors.New("api_token can't be used together with user and password, set only one of them")
+	}
+
+	if !meta.supports(capabilityAPIToken) {
+		return fmt.Errorf("api_token requires Zabbix Server 5.4 or higher, got version %s", meta.serverVersion)
 	}
-	log.Printf("[DEBUG] Zabbix Server version is %s\n", v)
 
-	return v
+	// API tokens are sent as the auth field of each request, user.login is skipped
+	api.Auth = token
+
+	return nil
+}
+
+func isProviderCredentialSet(value string) bool {
+	return value != "" && value != "undef"
+}
+
+// getZabbixServerVersion return the version cached when the provider was configured
+func getZabbixServerVersion(meta interface{}) string {
+	return meta.(*providerMeta).serverVersion
 }
 
 func isZabbixServerVersion34OrHigher(zabbixVersion string) bool {
-	return version.Compare(zabbixVersion, "3.4.0", ">=")
+	return isCapabilitySupported(zabbixVersion, capabilityTimeUnits)
 }
 
 func getZabbixServerUnitDays(zabbixVersion string) string {
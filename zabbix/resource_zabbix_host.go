@@ -1,11 +1,16 @@
 package zabbix
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
 )
@@ -33,6 +38,10 @@ func resourceZabbixHost() *schema.Resource {
 		Read:   resourceZabbixHostRead,
 		Update: resourceZabbixHostUpdate,
 		Delete: resourceZabbixHostDelete,
+		Importer: &schema.ResourceImporter{
+			State: importByIDOrName("host", "host.get", "hostid", "host"),
+		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"host": &schema.Schema{
 				Type:        schema.TypeString,
@@ -57,30 +66,54 @@ func resourceZabbixHost() *schema.Resource {
 				Default:  true,
 				Optional: true,
 			},
-			//any changes to interface will trigger recreate, zabbix api kinda doesn't
-			//work nicely, interface can get linked to various things and replacement
-			//simply doesn't work
 			"interfaces": &schema.Schema{
-				Type:     schema.TypeList,
-				Elem:     interfaceSchema,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeList,
+				Elem:        interfaceSchema,
+				Required:    true,
+				Description: "Interfaces of the host, updated in place and matched by interface_id. Exactly one interface of each type must be main.",
 			},
 			"groups": &schema.Schema{
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+				Type:         schema.TypeSet,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				AtLeastOneOf: []string{"groups", "group_ids"},
+				Description:  "Names of the host groups.",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of the host groups, not affected by group renames.",
+			},
+			"create_missing_groups": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create the groups which don't exist yet instead of failing.",
 			},
 			"templates": &schema.Schema{
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
+			"template_unlink_mode": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "unlink",
+				Description: "What happens to the items of the templates removed from templates: unlink keeps them on the host, clear deletes them.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(string)
+					if v != "unlink" && v != "clear" {
+						errs = append(errs, fmt.Errorf("%q must be unlink or clear, got: %s", key, v))
+					}
+					return
+				},
+			},
 			"tags": &schema.Schema{
 				Type:        schema.TypeMap,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "Tags for host.",
+				Description: "Tags for host. Support in Zabbix >=4.2",
 			},
 			"macro": &schema.Schema{
 				Type:        schema.TypeMap,
@@ -89,114 +122,669 @@ func resourceZabbixHost() *schema.Resource {
 				Description: "User macros for the host.",
 			},
 			"proxy_hostid": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Default:       "0",
+				ConflictsWith: []string{"monitored_by"},
+				Deprecated:    "Use monitored_by instead, it supports proxy names and proxy groups",
+			},
+			"monitored_by": &schema.Schema{
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Elem:        monitoredBySchema,
+				Description: "Proxy or proxy group (Zabbix >=7.0) monitoring the host, by name or by id. The host is monitored by the server when unset.",
+			},
+			"monitored_by_type": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "What monitors the host according to the server: server, proxy or proxy_group.",
+			},
+			"inventory_mode": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Host inventory mode: disabled, manual or automatic. Left to the server default when unset.",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(string)
+					if _, ok := HostInventoryModes[v]; !ok {
+						errs = append(errs, fmt.Errorf("%q must be one of disabled, manual or automatic, got: %s", key, v))
+					}
+					return
+				},
+			},
+			"inventory": &schema.Schema{
+				Type:         schema.TypeMap,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				ValidateFunc: validateHostInventoryFields,
+				Description:  "Host inventory fields, e.g. serialno_a or location. In automatic mode, the fields populated by items keep their configured value.",
+			},
+			"tls_connect": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "Connections to the host: 1 (no encryption), 2 (PSK) or 4 (certificate).",
+			},
+			"tls_accept": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "Connections accepted from the host, sum of 1 (no encryption), 2 (PSK) and 4 (certificate).",
+			},
+			"tls_issuer": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate issuer.",
+			},
+			"tls_subject": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate subject.",
+			},
+			"tls_psk_identity": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PSK identity, required with PSK encryption.",
+			},
+			"tls_psk": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "PSK of at least 32 hex digits, generated when generate_tls_psk is set.",
+			},
+			"generate_tls_psk": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Generate a random PSK when tls_psk is not set.",
+			},
+			"ipmi_authtype": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     -1,
+				Description: "IPMI authentication algorithm: -1 (default), 0 (none), 1 (MD2), 2 (MD5), 4 (straight), 5 (OEM) or 6 (RMCP+).",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < -1 || v > 6 || v == 3 {
+						errs = append(errs, fmt.Errorf("%q must be one of -1, 0, 1, 2, 4, 5 or 6, got %d", key, v))
+					}
+					return
+				},
+			},
+			"ipmi_privilege": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     2,
+				Description: "IPMI privilege level: 1 (callback), 2 (user), 3 (operator), 4 (admin) or 5 (OEM).",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < 1 || v > 5 {
+						errs = append(errs, fmt.Errorf("%q, must be between 1 and 5 inclusive, got %d", key, v))
+					}
+					return
+				},
+			},
+			"ipmi_username": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IPMI username.",
+			},
+			"ipmi_password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "IPMI password.",
+			},
+			"on_destroy": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "0",
+				Default:  "delete",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if _, ok := hostDestroyModes[val.(string)]; !ok {
+						errs = append(errs, fmt.Errorf("%q must be one of delete, disable or disable_and_move, got %q", key, val))
+					}
+					return
+				},
+				Description: "Action on destroy: delete the host, disable it, or disable it and move it to archive_group to keep its history.",
+			},
+			"archive_group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the host group the host is moved to when destroyed with on_destroy = disable_and_move.",
+			},
+			"decommission_tag": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Tag set to the time of destruction on hosts disabled instead of deleted.",
+			},
+			"wait_for_availability": &schema.Schema{
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Elem:        waitForAvailabilitySchema,
+				Description: "Wait for the interfaces to become available after the host is created and after its interfaces change.",
+			},
+			"availability": &schema.Schema{
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "Availability of the main interface of each type: unknown, available or unavailable, only read when wait_for_availability is set.",
+			},
+			"availability_error": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Errors of the unavailable interfaces, only read when wait_for_availability is set.",
 			},
 		},
+		CustomizeDiff: customdiff.All(
+			validateCapabilities(map[string]capability{
+				"tags":                          capabilityHostTags,
+				"decommission_tag":              capabilityHostTags,
+				"monitored_by.0.proxy_group":    capabilityProxyGroups,
+				"monitored_by.0.proxy_group_id": capabilityProxyGroups,
+			}),
+			validateHostInterfaces,
+			validateHostInventory,
+			validateHostTLS,
+			validateHostDestroy,
+		),
 	}
 }
 
-func createInterfacesObj(d *schema.ResourceData) (zabbix.HostInterfaces, error) {
-	interfaceCount := d.Get("interfaces.#").(int)
+// hostInterface is an interface of the hostinterface API, the numbers are
+// strings as returned by the API
+type hostInterface struct {
+	InterfaceID string `json:"interfaceid,omitempty"`
+	HostID      string `json:"hostid,omitempty"`
+	Main        string `json:"main"`
+	Type        string `json:"type"`
+	UseIP       string `json:"useip"`
+	IP          string `json:"ip"`
+	DNS         string `json:"dns"`
+	Port        string `json:"port"`
+
+	Details *hostInterfaceDetails `json:"details,omitempty"`
+}
 
-	interfaces := make(zabbix.HostInterfaces, interfaceCount)
+// hostInterfaceDetails are the SNMP settings of an interface, only the fields
+// of the SNMP version are sent
+type hostInterfaceDetails struct {
+	Version        string `json:"version"`
+	Bulk           string `json:"bulk"`
+	Community      string `json:"community,omitempty"`
+	SecurityName   string `json:"securityname,omitempty"`
+	SecurityLevel  string `json:"securitylevel,omitempty"`
+	AuthProtocol   string `json:"authprotocol,omitempty"`
+	AuthPassphrase string `json:"authpassphrase,omitempty"`
+	PrivProtocol   string `json:"privprotocol,omitempty"`
+	PrivPassphrase string `json:"privpassphrase,omitempty"`
+	ContextName    string `json:"contextname,omitempty"`
+}
 
-	for i := 0; i < interfaceCount; i++ {
-		prefix := fmt.Sprintf("interfaces.%d.", i)
+// UnmarshalJSON accept the empty array returned for the interfaces without details
+func (details *hostInterfaceDetails) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nil
+	}
 
-		interfaceType := d.Get(prefix + "type").(string)
+	type plainDetails hostInterfaceDetails
+	return json.Unmarshal(data, (*plainDetails)(details))
+}
 
-		typeID, ok := HostInterfaceTypes[interfaceType]
+// hostSettings are the host fields zabbix.Host is missing
+type hostSettings struct {
+	InventoryMode string        `json:"inventory_mode,omitempty"`
+	Inventory     hostInventory `json:"inventory,omitempty"`
+
+	TLSConnect     string `json:"tls_connect,omitempty"`
+	TLSAccept      string `json:"tls_accept,omitempty"`
+	TLSIssuer      string `json:"tls_issuer"`
+	TLSSubject     string `json:"tls_subject"`
+	TLSPSKIdentity string `json:"tls_psk_identity"`
+	TLSPSK         string `json:"tls_psk"`
+
+	IPMIAuthType  string `json:"ipmi_authtype,omitempty"`
+	IPMIPrivilege string `json:"ipmi_privilege,omitempty"`
+	IPMIUsername  string `json:"ipmi_username"`
+	IPMIPassword  string `json:"ipmi_password"`
+
+	// monitored_by replaced proxy_hostid in Zabbix 7.0
+	MonitoredBy  string `json:"monitored_by,omitempty"`
+	ProxyID      string `json:"proxyid,omitempty"`
+	ProxyGroupID string `json:"proxy_groupid,omitempty"`
+}
 
-		if !ok {
-			return nil, fmt.Errorf("%s isnt valid interface type", interfaceType)
+// hostSettingsFields is the output of host.get to read hostSettings, the PSK
+// can't be read since Zabbix 5.4
+func hostSettingsFields(m *providerMeta) []string {
+	fields := []string{
+		"inventory_mode",
+		"tls_connect", "tls_accept", "tls_issuer", "tls_subject",
+		"ipmi_authtype", "ipmi_privilege", "ipmi_username", "ipmi_password",
+	}
+	if m.supports(capabilityReadableTLSPSK) {
+		fields = append(fields, "tls_psk_identity", "tls_psk")
+	}
+	if m.supports(capabilityProxyGroups) {
+		fields = append(fields, "monitored_by", "proxyid", "proxy_groupid")
+	}
+	return fields
+}
+
+// hostObject is the host sent to the API, with the fields zabbix.Host is missing
+type hostObject struct {
+	zabbix.Host
+	hostSettings
+	Interfaces     []hostInterface    `json:"interfaces,omitempty"`
+	TemplatesClear zabbix.TemplateIDs `json:"templates_clear,omitempty"`
+
+	// ProxyHostID shadows the proxy_hostid of zabbix.Host, which is rejected
+	// by Zabbix 7.0
+	ProxyHostID string `json:"proxy_hostid,omitempty"`
+}
+
+func getHostSettings(m *providerMeta, hostID string) (*hostSettings, error) {
+	var hosts []hostSettings
+
+	err := m.api.CallWithErrorParse("host.get", zabbix.Params{
+		"output":          hostSettingsFields(m),
+		"selectInventory": "extend",
+		"hostids":         hostID,
+	}, &hosts)
+
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) != 1 {
+		return nil, newNotFoundError("Host %s doesn't exist", hostID)
+	}
+	return &hosts[0], nil
+}
+
+func createInterfacesObj(d *schema.ResourceData) ([]hostInterface, error) {
+	return expandHostInterfaces(d.Get("interfaces").([]interface{}))
+}
+
+func expandHostInterfaces(list []interface{}) ([]hostInterface, error) {
+	interfaces := make([]hostInterface, len(list))
+
+	for i, v := range list {
+		hostInterface, err := expandHostInterface(v.(map[string]interface{}))
+		if err != nil {
+			return nil, err
 		}
+		interfaces[i] = hostInterface
+	}
+
+	if err := checkMainInterfaces(interfaces); err != nil {
+		return nil, err
+	}
+
+	return interfaces, nil
+}
+
+func expandHostInterface(value map[string]interface{}) (hostInterface, error) {
+	interfaceType := value["type"].(string)
+
+	typeID, ok := HostInterfaceTypes[interfaceType]
+
+	if !ok {
+		return hostInterface{}, fmt.Errorf("%s isnt valid interface type", interfaceType)
+	}
+
+	ip := value["ip"].(string)
+	dns := value["dns"].(string)
+
+	if ip == "" && dns == "" {
+		return hostInterface{}, errors.New("Atleast one of two dns or ip must be set")
+	}
 
-		ip := d.Get(prefix + "ip").(string)
-		dns := d.Get(prefix + "dns").(string)
+	useip := "1"
 
-		if ip == "" && dns == "" {
-			return nil, errors.New("Atleast one of two dns or ip must be set")
+	if ip == "" {
+		useip = "0"
+	}
+
+	main := "0"
+
+	if value["main"].(bool) {
+		main = "1"
+	}
+
+	result := hostInterface{
+		InterfaceID: value["interface_id"].(string),
+		IP:          ip,
+		DNS:         dns,
+		Main:        main,
+		Port:        value["port"].(string),
+		Type:        fmt.Sprintf("%d", typeID),
+		UseIP:       useip,
+	}
+
+	if details := value["details"].([]interface{}); len(details) == 1 && details[0] != nil {
+		if interfaceType != "snmp" {
+			return hostInterface{}, fmt.Errorf("details can only be set on snmp interfaces, got a %s interface", interfaceType)
 		}
+		result.Details = expandHostInterfaceDetails(details[0].(map[string]interface{}))
+	}
 
-		useip := 1
+	return result, nil
+}
+
+func expandHostInterfaceDetails(value map[string]interface{}) *hostInterfaceDetails {
+	details := &hostInterfaceDetails{
+		Version: fmt.Sprintf("%d", value["version"].(int)),
+		Bulk:    "0",
+	}
 
-		if ip == "" {
-			useip = 0
+	if value["bulk"].(bool) {
+		details.Bulk = "1"
+	}
+
+	if details.Version == "3" {
+		details.SecurityName = value["securityname"].(string)
+		details.SecurityLevel = fmt.Sprintf("%d", value["securitylevel"].(int))
+		details.AuthProtocol = fmt.Sprintf("%d", value["authprotocol"].(int))
+		details.AuthPassphrase = value["authpassphrase"].(string)
+		details.PrivProtocol = fmt.Sprintf("%d", value["privprotocol"].(int))
+		details.PrivPassphrase = value["privpassphrase"].(string)
+		details.ContextName = value["contextname"].(string)
+	} else {
+		details.Community = value["community"].(string)
+	}
+
+	return details
+}
+
+func flattenHostInterfaceDetails(details *hostInterfaceDetails) []interface{} {
+	if details == nil || details.Version == "" {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"version":        atoiOrZero(details.Version),
+			"bulk":           details.Bulk == "1",
+			"community":      details.Community,
+			"securityname":   details.SecurityName,
+			"securitylevel":  atoiOrZero(details.SecurityLevel),
+			"authprotocol":   atoiOrZero(details.AuthProtocol),
+			"authpassphrase": details.AuthPassphrase,
+			"privprotocol":   atoiOrZero(details.PrivProtocol),
+			"privpassphrase": details.PrivPassphrase,
+			"contextname":    details.ContextName,
+		},
+	}
+}
+
+// checkMainInterfaces ensure there is exactly one main interface of each type used
+func checkMainInterfaces(interfaces []hostInterface) error {
+	mains := make(map[string]int)
+	for _, i := range interfaces {
+		count := mains[i.Type]
+		if i.Main == "1" {
+			count++
 		}
+		mains[i.Type] = count
+	}
 
-		main := 1
+	for typeID, count := range mains {
+		if count != 1 {
+			return fmt.Errorf("Exactly one main interface of type %s is required, got %d", interfaceTypeName(typeID), count)
+		}
+	}
+	return nil
+}
 
-		if !d.Get(prefix + "main").(bool) {
-			main = 1
+func interfaceTypeName(typeID string) string {
+	for name, id := range HostInterfaceTypes {
+		if fmt.Sprintf("%d", id) == typeID {
+			return name
 		}
+	}
+	return typeID
+}
 
-		interfaces[i] = zabbix.HostInterface{
-			IP:    ip,
-			DNS:   dns,
-			Main:  main,
-			Port:  d.Get(prefix + "port").(string),
-			Type:  typeID,
-			UseIP: useip,
+func flattenHostInterfaces(interfaces []hostInterface) []interface{} {
+	list := make([]interface{}, len(interfaces))
+
+	for i, hostInterface := range interfaces {
+		list[i] = map[string]interface{}{
+			"interface_id": hostInterface.InterfaceID,
+			"dns":          hostInterface.DNS,
+			"ip":           hostInterface.IP,
+			"main":         hostInterface.Main == "1",
+			"port":         hostInterface.Port,
+			"type":         interfaceTypeName(hostInterface.Type),
+			"details":      flattenHostInterfaceDetails(hostInterface.Details),
 		}
 	}
+	return list
+}
 
-	return interfaces, nil
+func getHostInterfaces(api *zabbix.API, hostID string) ([]hostInterface, error) {
+	var interfaces []hostInterface
+
+	err := api.CallWithErrorParse("hostinterface.get", zabbix.Params{
+		"output":  "extend",
+		"hostids": hostID,
+	}, &interfaces)
+
+	return interfaces, err
 }
 
-func getHostGroups(d *schema.ResourceData, api *zabbix.API) (zabbix.HostGroupIDs, error) {
-	configGroups := d.Get("groups").(*schema.Set)
-	setHostGroups := make([]string, configGroups.Len())
+// sortHostInterfaces put interfaces in the order of the interface ids in state,
+// the interfaces unknown to terraform come last
+func sortHostInterfaces(interfaces []hostInterface, order []string) []hostInterface {
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+
+	sort.SliceStable(interfaces, func(i, j int) bool {
+		pi, oki := position[interfaces[i].InterfaceID]
+		pj, okj := position[interfaces[j].InterfaceID]
+		if oki != okj {
+			return oki
+		}
+		return pi < pj
+	})
+	return interfaces
+}
 
-	for i, g := range configGroups.List() {
-		setHostGroups[i] = g.(string)
+// updateHostInterfaces apply the changes of the interfaces without recreating
+// the host. Interfaces are matched by interface_id, the main flag of each type
+// is moved so the host always keeps one main interface per type
+func updateHostInterfaces(d *schema.ResourceData, api *zabbix.API) error {
+	interfaces, err := createInterfacesObj(d)
+	if err != nil {
+		return err
 	}
 
-	log.Printf("[DEBUG] Groups %v\n", setHostGroups)
+	current, err := getHostInterfaces(api, d.Id())
+	if err != nil {
+		return err
+	}
 
-	groupParams := zabbix.Params{
-		"output": "extend",
-		"filter": map[string]interface{}{
-			"name": setHostGroups,
-		},
+	currentByID := make(map[string]hostInterface, len(current))
+	currentMains := make(map[string]bool)
+	for _, c := range current {
+		currentByID[c.InterfaceID] = c
+		if c.Main == "1" {
+			currentMains[c.Type] = true
+		}
 	}
 
-	groups, err := api.HostGroupsGet(groupParams)
+	kept := make(map[string]bool)
+	usedTypes := make(map[string]bool)
+	updates := []interface{}{}
+	creates := []hostInterface{}
+	promoted := []bool{}
 
-	if err != nil {
-		return nil, err
+	for _, i := range interfaces {
+		usedTypes[i.Type] = true
+
+		if _, ok := currentByID[i.InterfaceID]; ok {
+			kept[i.InterfaceID] = true
+			updates = append(updates, i)
+			continue
+		}
+
+		i.InterfaceID = ""
+		i.HostID = d.Id()
+
+		// a second main interface can't be created, it's promoted once created
+		promote := i.Main == "1" && currentMains[i.Type]
+		if promote {
+			i.Main = "0"
+		}
+		creates = append(creates, i)
+		promoted = append(promoted, promote)
 	}
 
-	if len(groups) < configGroups.Len() {
-		log.Printf("[DEBUG] Not all of the specified groups were found on zabbix server")
+	deletes := []string{}
+	for _, c := range current {
+		if kept[c.InterfaceID] {
+			continue
+		}
+		deletes = append(deletes, c.InterfaceID)
+		if c.Main == "1" && usedTypes[c.Type] {
+			updates = append(updates, map[string]string{"interfaceid": c.InterfaceID, "main": "0"})
+		}
+	}
 
-		for _, n := range configGroups.List() {
-			found := false
+	timeout := d.Timeout(schema.TimeoutUpdate)
 
-			for _, g := range groups {
-				if n == g.Name {
-					found = true
-					break
-				}
+	if len(creates) > 0 {
+		var result struct {
+			InterfaceIDs []string `json:"interfaceids"`
+		}
+		err := apiRetry(timeout, func() error {
+			return api.CallWithErrorParse("hostinterface.create", creates, &result)
+		})
+		if err != nil {
+			return describeAPIError(err, "Failed to create interfaces of host %s", d.Id())
+		}
+		for i, id := range result.InterfaceIDs {
+			if i < len(promoted) && promoted[i] {
+				updates = append(updates, map[string]string{"interfaceid": id, "main": "1"})
 			}
+		}
+	}
 
-			if !found {
-				return nil, fmt.Errorf("Host group %s doesnt exist in zabbix server", n)
-			}
-			log.Printf("[DEBUG] %s exists on zabbix server", n)
+	if len(updates) > 0 {
+		err := apiRetry(timeout, func() error {
+			_, err := api.CallWithError("hostinterface.update", updates)
+			return err
+		})
+		if err != nil {
+			return describeAPIError(err, "Failed to update interfaces of host %s", d.Id())
+		}
+	}
+
+	if len(deletes) > 0 {
+		err := apiRetry(timeout, func() error {
+			_, err := api.CallWithError("hostinterface.delete", deletes)
+			return err
+		})
+		if err != nil {
+			return describeAPIError(err, "Failed to delete interfaces of host %s", d.Id())
 		}
 	}
 
-	hostGroups := make(zabbix.HostGroupIDs, len(groups))
+	return nil
+}
+
+// validateHostInventory is a CustomizeDiff rejecting inventory fields on hosts
+// whose inventory is disabled
+func validateHostInventory(d *schema.ResourceDiff, meta interface{}) error {
+	inventory := d.Get("inventory").(map[string]interface{})
+	if len(inventory) > 0 && d.Get("inventory_mode").(string) == "disabled" {
+		return errors.New("inventory can't be set when inventory_mode is disabled")
+	}
+	return nil
+}
+
+// validateHostTLS is a CustomizeDiff checking the PSK settings when PSK is used
+func validateHostTLS(d *schema.ResourceDiff, meta interface{}) error {
+	usePSK := d.Get("tls_connect").(int) == 2 || d.Get("tls_accept").(int)&2 != 0
+	if !usePSK {
+		return nil
+	}
+	if d.NewValueKnown("tls_psk_identity") && d.Get("tls_psk_identity").(string) == "" {
+		return errors.New("tls_psk_identity is required when tls_connect or tls_accept use PSK")
+	}
+	if d.NewValueKnown("tls_psk") && d.Get("tls_psk").(string) == "" && !d.Get("generate_tls_psk").(bool) {
+		return errors.New("tls_psk or generate_tls_psk is required when tls_connect or tls_accept use PSK")
+	}
+	return nil
+}
+
+// hostDestroyModes are the values of on_destroy
+var hostDestroyModes = map[string]bool{
+	"delete":           true,
+	"disable":          true,
+	"disable_and_move": true,
+}
 
-	for i, g := range groups {
-		hostGroups[i] = zabbix.HostGroupID{
-			GroupID: g.GroupID,
+func validateHostDestroy(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("on_destroy").(string) == "disable_and_move" && d.NewValueKnown("archive_group") && d.Get("archive_group").(string) == "" {
+		return errors.New("archive_group is required when on_destroy is disable_and_move")
+	}
+	return nil
+}
+
+// validateHostInterfaces is a CustomizeDiff checking the main interfaces and the
+// SNMP details at plan time
+func validateHostInterfaces(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("interfaces") {
+		return nil
+	}
+
+	// interfaces whose values come from resources not created yet are
+	// checked once they are known
+	var interfaces []hostInterface
+	allKnown := true
+	for i, v := range d.Get("interfaces").([]interface{}) {
+		known := true
+		for _, field := range []string{"ip", "dns", "main", "type"} {
+			if !d.NewValueKnown(fmt.Sprintf("interfaces.%d.%s", i, field)) {
+				known = false
+			}
+		}
+		if !known {
+			allKnown = false
+			continue
+		}
+
+		hostInterface, err := expandHostInterface(v.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		interfaces = append(interfaces, hostInterface)
+	}
+
+	if allKnown {
+		if err := checkMainInterfaces(interfaces); err != nil {
+			return err
 		}
 	}
 
-	return hostGroups, nil
+	m := meta.(*providerMeta)
+	for _, i := range interfaces {
+		if i.Type != fmt.Sprintf("%d", HostInterfaceTypes["snmp"]) {
+			continue
+		}
+		if i.Details != nil && !m.supports(capabilityInterfaceDetails) {
+			return fmt.Errorf("SNMP interface details can't be used with Zabbix Server %s, %s are available %s", m.serverVersion, capabilityInterfaceDetails, capabilityVersionRange(capabilityInterfaceDetails))
+		}
+		if i.Details == nil && m.supports(capabilityInterfaceDetails) {
+			return fmt.Errorf("details are required by the snmp interfaces on Zabbix Server %s", m.serverVersion)
+		}
+	}
+	return nil
 }
 
 func getTemplates(d *schema.ResourceData, api *zabbix.API) (zabbix.TemplateIDs, error) {
@@ -233,7 +821,7 @@ func getTemplates(d *schema.ResourceData, api *zabbix.API) (zabbix.TemplateIDs,
 			found := false
 
 			for _, g := range templates {
-				if n == g.Name {
+				if n == g.Host {
 					found = true
 					break
 				}
@@ -257,14 +845,94 @@ func getTemplates(d *schema.ResourceData, api *zabbix.API) (zabbix.TemplateIDs,
 	return hostTemplates, nil
 }
 
-func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error) {
-	host := zabbix.Host{
-		Host:        d.Get("host").(string),
-		Name:        d.Get("name").(string),
-		Status:      0,
-		UserMacros:  createZabbixMacro(d),
-		Tags:        createZabbixTag(d),
-		ProxyHostId: d.Get("proxy_hostid").(string),
+// getClearedTemplates return the templates removed from templates when they
+// must be cleared, their items are then removed from the host with the link
+func getClearedTemplates(d *schema.ResourceData, api *zabbix.API) (zabbix.TemplateIDs, error) {
+	if d.Get("template_unlink_mode").(string) != "clear" || !d.HasChange("templates") {
+		return nil, nil
+	}
+
+	before, after := d.GetChange("templates")
+	removed := before.(*schema.Set).Difference(after.(*schema.Set))
+	if removed.Len() == 0 {
+		return nil, nil
+	}
+
+	templateNames := make([]string, removed.Len())
+	for i, t := range removed.List() {
+		templateNames[i] = t.(string)
+	}
+
+	log.Printf("[DEBUG] Templates to clear %v\n", templateNames)
+
+	// templates deleted meanwhile are already unlinked
+	templates, err := api.TemplatesGet(zabbix.Params{
+		"output": "extend",
+		"filter": map[string]interface{}{
+			"host": templateNames,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	clearedTemplates := make(zabbix.TemplateIDs, len(templates))
+
+	for i, t := range templates {
+		clearedTemplates[i] = zabbix.TemplateID{
+			TemplateID: t.TemplateID,
+		}
+	}
+
+	return clearedTemplates, nil
+}
+
+func createHostObj(d *schema.ResourceData, m *providerMeta) (*hostObject, error) {
+	api := m.api
+
+	host := hostObject{
+		Host: zabbix.Host{
+			Host:       d.Get("host").(string),
+			Name:       d.Get("name").(string),
+			Status:     0,
+			UserMacros: createZabbixMacro(d),
+			Tags:       createZabbixTag(d),
+		},
+	}
+
+	monitoring, err := expandHostMonitoring(d, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := monitoring.apply(&host, m); err != nil {
+		return nil, err
+	}
+
+	host.InventoryMode = HostInventoryModes[d.Get("inventory_mode").(string)]
+	host.Inventory = createHostInventory(d)
+
+	host.TLSConnect = fmt.Sprintf("%d", d.Get("tls_connect").(int))
+	host.TLSAccept = fmt.Sprintf("%d", d.Get("tls_accept").(int))
+	host.TLSIssuer = d.Get("tls_issuer").(string)
+	host.TLSSubject = d.Get("tls_subject").(string)
+	host.TLSPSKIdentity = d.Get("tls_psk_identity").(string)
+	host.TLSPSK = d.Get("tls_psk").(string)
+
+	host.IPMIAuthType = fmt.Sprintf("%d", d.Get("ipmi_authtype").(int))
+	host.IPMIPrivilege = fmt.Sprintf("%d", d.Get("ipmi_privilege").(int))
+	host.IPMIUsername = d.Get("ipmi_username").(string)
+	host.IPMIPassword = d.Get("ipmi_password").(string)
+
+	if d.Get("generate_tls_psk").(bool) && host.TLSPSK == "" {
+		randomPsk, err := randomHex(32)
+
+		if err != nil {
+			return nil, err
+		}
+
+		host.TLSPSK = randomPsk
+		d.Set("tls_psk", randomPsk)
 	}
 
 	//0 is monitored, 1 - unmonitored host
@@ -272,13 +940,18 @@ func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error
 		host.Status = 1
 	}
 
-	hostGroups, err := getHostGroups(d, api)
+	groupIDs, err := getGroupIDs(d, api, hostGroupAPI)
 
 	if err != nil {
 		return nil, err
 	}
 
-	host.GroupIds = hostGroups
+	host.GroupIds = make(zabbix.HostGroupIDs, len(groupIDs))
+	for i, id := range groupIDs {
+		host.GroupIds[i] = zabbix.HostGroupID{
+			GroupID: id,
+		}
+	}
 
 	interfaces, err := createInterfacesObj(d)
 
@@ -307,51 +980,130 @@ func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error
 }
 
 func resourceZabbixHostCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	host, err := createHostObj(d, api)
+	host, err := createHostObj(d, meta.(*providerMeta))
 
 	if err != nil {
 		return err
 	}
 
-	hosts := zabbix.Hosts{*host}
+	var result struct {
+		HostIDs []string `json:"hostids"`
+	}
 
-	err = api.HostsCreate(hosts)
+	err = apiRetry(d.Timeout(schema.TimeoutCreate), func() error {
+		return api.CallWithErrorParse("host.create", host, &result)
+	})
 
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] Created host id is %s", hosts[0].HostID)
-	log.Printf("[DEBUG] All data for host %s", hosts[0].Host)
+	if len(result.HostIDs) != 1 {
+		return fmt.Errorf("Expected one host id to be created, got %d", len(result.HostIDs))
+	}
 
-	d.Set("host_id", hosts[0].HostID)
-	d.SetId(hosts[0].HostID)
+	log.Printf("[DEBUG] Created host id is %s", result.HostIDs[0])
+	log.Printf("[DEBUG] All data for host %s", host.Host.Host)
 
-	return nil
+	d.Set("host_id", result.HostIDs[0])
+	d.SetId(result.HostIDs[0])
+
+	if err := waitForHostAvailability(d, meta.(*providerMeta)); err != nil {
+		return err
+	}
+
+	return resourceZabbixHostRead(d, meta)
 }
 
 func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	log.Printf("[DEBUG] Will read host with id %s", d.Get("host_id").(string))
+	log.Printf("[DEBUG] Will read host with id %s", d.Id())
 
-	host, err := api.HostGetByID(d.Get("host_id").(string))
+	host, err := api.HostGetByID(d.Id())
 
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read host %s", d.Id())
 	}
 
 	log.Printf("[DEBUG] Host name is %s", host.Name)
 
+	d.Set("host_id", d.Id())
 	d.Set("host", host.Host)
 	d.Set("name", host.Name)
 
-	d.Set("proxy_hostid", host.ProxyHostId)
-
 	d.Set("monitored", host.Status == 0)
 
+	interfaces, err := getHostInterfaces(api, d.Id())
+
+	if err != nil {
+		return describeAPIError(err, "Failed to read interfaces of host %s", d.Id())
+	}
+
+	settings, err := getHostSettings(meta.(*providerMeta), d.Id())
+
+	if err != nil {
+		return readError(d, err, "Failed to read settings of host %s", d.Id())
+	}
+
+	if err := readHostMonitoring(d, meta.(*providerMeta), hostMonitoringOf(meta.(*providerMeta), host.ProxyHostId, settings)); err != nil {
+		return describeAPIError(err, "Failed to read proxy of host %s", d.Id())
+	}
+
+	inventoryMode := settings.InventoryMode
+	if inventoryMode == "" {
+		// before Zabbix 4.4 the mode is a field of the inventory
+		inventoryMode = settings.Inventory["inventory_mode"]
+	}
+	if inventoryMode == "" {
+		inventoryMode = HostInventoryModes["disabled"]
+	}
+	d.Set("inventory_mode", hostInventoryModeName(inventoryMode))
+
+	d.Set("tls_connect", atoiOrZero(settings.TLSConnect))
+	d.Set("tls_accept", atoiOrZero(settings.TLSAccept))
+	d.Set("tls_issuer", settings.TLSIssuer)
+	d.Set("tls_subject", settings.TLSSubject)
+
+	d.Set("ipmi_authtype", atoiOrZero(settings.IPMIAuthType))
+	d.Set("ipmi_privilege", atoiOrZero(settings.IPMIPrivilege))
+	d.Set("ipmi_username", settings.IPMIUsername)
+	d.Set("ipmi_password", settings.IPMIPassword)
+
+	// the PSK is write only since Zabbix 5.4, the state keeps the configured one
+	if meta.(*providerMeta).supports(capabilityReadableTLSPSK) {
+		d.Set("tls_psk_identity", settings.TLSPSKIdentity)
+		d.Set("tls_psk", settings.TLSPSK)
+	}
+
+	var itemInventoryFields map[string]bool
+	if inventoryMode == HostInventoryModes["automatic"] {
+		itemInventoryFields, err = getItemInventoryFields(api, d.Id())
+		if err != nil {
+			return describeAPIError(err, "Failed to read inventory items of host %s", d.Id())
+		}
+	}
+	d.Set("inventory", flattenHostInventory(settings.Inventory, d.Get("inventory").(map[string]interface{}), inventoryMode, itemInventoryFields))
+
+	stateInterfaces := d.Get("interfaces").([]interface{})
+	order := make([]string, len(stateInterfaces))
+	for i, v := range stateInterfaces {
+		order[i] = v.(map[string]interface{})["interface_id"].(string)
+	}
+
+	d.Set("interfaces", flattenHostInterfaces(sortHostInterfaces(interfaces, order)))
+
+	// the availability costs an extra call, it is only refreshed for the hosts waiting for it
+	if blocks := d.Get("wait_for_availability").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		availability, err := getHostAvailability(meta.(*providerMeta), d.Id())
+		if err != nil {
+			return readError(d, err, "Failed to read availability of host %s", d.Id())
+		}
+		setHostAvailability(d, availability)
+	}
+
 	templates, err := api.TemplatesGet(zabbix.Params{
 		"output":       "extend",
 		"selectMacros": "extend",
@@ -373,24 +1125,10 @@ func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.Set("templates", templateNames)
 
-	groups, err := api.HostGroupsGet(zabbix.Params{
-		"output": "extend",
-		"hostids": []string{
-			d.Id(),
-		},
-	})
-
-	if err != nil {
+	if err := readGroups(d, api, hostGroupAPI, d.Id()); err != nil {
 		return err
 	}
 
-	groupNames := make([]string, len(groups))
-
-	for i, g := range groups {
-		groupNames[i] = g.Name
-	}
-
-	d.Set("groups", groupNames)
 	terraformTags, err := createTerraformTag(host)
 	log.Printf("[DEBUG] Host tags is %s", host.Tags)
 	if err != nil {
@@ -408,9 +1146,9 @@ func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixHostUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	host, err := createHostObj(d, api)
+	host, err := createHostObj(d, meta.(*providerMeta))
 
 	if err != nil {
 		return err
@@ -418,27 +1156,110 @@ func resourceZabbixHostUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	host.HostID = d.Id()
 
-	//interfaces can't be updated, changes will trigger recreate
-	//sending previous values will also fail the update
-	host.Interfaces = nil
+	host.TemplatesClear, err = getClearedTemplates(d, api)
 
-	hosts := zabbix.Hosts{*host}
+	if err != nil {
+		return err
+	}
 
-	err = api.HostsUpdate(hosts)
+	//interfaces are updated through the hostinterface API, sending them
+	//with the host would replace them
+	host.Interfaces = nil
+
+	err = apiRetry(d.Timeout(schema.TimeoutUpdate), func() error {
+		_, err := api.CallWithError("host.update", host)
+		return err
+	})
 
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] Created host id is %s", hosts[0].HostID)
+	if d.HasChange("interfaces") {
+		if err := updateHostInterfaces(d, api); err != nil {
+			return err
+		}
+		if err := waitForHostAvailability(d, meta.(*providerMeta)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Updated host id is %s", d.Id())
 
-	return nil
+	return resourceZabbixHostRead(d, meta)
 }
 
 func resourceZabbixHostDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
+
+	// hosts in a state written before on_destroy existed have an empty value
+	// and are deleted
+	switch d.Get("on_destroy").(string) {
+	case "disable", "disable_and_move":
+		return decommissionHost(d, api)
+	}
 
-	return api.HostsDeleteByIds([]string{d.Id()})
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.HostsDeleteByIds([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "Host", d.Id())
+}
+
+// decommissionHost disable the host instead of deleting it so its history is
+// kept, the host is moved to the archive group and tagged with the time of
+// destruction when configured
+func decommissionHost(d *schema.ResourceData, api *zabbix.API) error {
+	params := zabbix.Params{
+		"hostid": d.Id(),
+		"status": "1",
+	}
+
+	if d.Get("on_destroy").(string) == "disable_and_move" {
+		archiveGroup := d.Get("archive_group").(string)
+
+		ids, err := lookupObjects(api, "hostgroup.get", "name", "groupid", []string{archiveGroup})
+		if err != nil {
+			return describeAPIError(err, "Failed to read archive group %s", archiveGroup)
+		}
+		params["groups"] = []map[string]string{{"groupid": ids[archiveGroup]}}
+	}
+
+	if tag := d.Get("decommission_tag").(string); tag != "" {
+		var hosts []struct {
+			Tags zabbix.Tags `json:"tags"`
+		}
+		err := api.CallWithErrorParse("host.get", zabbix.Params{
+			"output":     []string{"hostid"},
+			"hostids":    d.Id(),
+			"selectTags": []string{"tag", "value"},
+		}, &hosts)
+		if err != nil {
+			return describeAPIError(err, "Failed to read tags of host %s", d.Id())
+		}
+		if len(hosts) == 0 {
+			log.Printf("[DEBUG] Host with id %s doesn't exist anymore", d.Id())
+			return nil
+		}
+
+		tags := zabbix.Tags{}
+		for _, t := range hosts[0].Tags {
+			if t.TagName != tag {
+				tags = append(tags, t)
+			}
+		}
+		params["tags"] = append(tags, zabbix.Tag{
+			TagName: tag,
+			Value:   time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	log.Printf("[DEBUG] Decommission host %s with %v", d.Id(), params)
+
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := api.CallWithError("host.update", params)
+		return err
+	})
+	return ignoreNotFound(err, "Host", d.Id())
 }
 
 func createTerraformMacroHost(host *zabbix.Host) (map[string]interface{}, error) {
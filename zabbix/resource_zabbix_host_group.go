@@ -2,7 +2,6 @@ package zabbix
 
 import (
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
@@ -15,6 +14,10 @@ func resourceZabbixHostGroup() *schema.Resource {
 		Exists: resourceZabbixHostGroupExists,
 		Update: resourceZabbixHostGroupUpdate,
 		Delete: resourceZabbixHostGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: importByIDOrName("host group", "hostgroup.get", "groupid", "name"),
+		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -31,14 +34,16 @@ func resourceZabbixHostGroup() *schema.Resource {
 }
 
 func resourceZabbixHostGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	hostGroup := zabbix.HostGroup{
 		Name: d.Get("name").(string),
 	}
 	groups := zabbix.HostGroups{hostGroup}
 
-	err := api.HostGroupsCreate(groups)
+	err := apiRetry(d.Timeout(schema.TimeoutCreate), func() error {
+		return api.HostGroupsCreate(groups)
+	})
 	if err != nil {
 		return err
 	}
@@ -54,27 +59,28 @@ func resourceZabbixHostGroupCreate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceZabbixHostGroupRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	log.Printf("[DEBUG] Will read host group with id %s", d.Id())
 
 	group, err := api.HostGroupGetByID(d.Id())
 
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read host group %s", d.Id())
 	}
 
+	d.Set("group_id", d.Id())
 	d.Set("name", group.Name)
 
 	return nil
 }
 
 func resourceZabbixHostGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.HostGroupGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Host group with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -84,18 +90,23 @@ func resourceZabbixHostGroupExists(d *schema.ResourceData, meta interface{}) (bo
 }
 
 func resourceZabbixHostGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	hostGroup := zabbix.HostGroup{
 		Name:    d.Get("name").(string),
 		GroupID: d.Id(),
 	}
 
-	return api.HostGroupsUpdate(zabbix.HostGroups{hostGroup})
+	return apiRetry(d.Timeout(schema.TimeoutUpdate), func() error {
+		return api.HostGroupsUpdate(zabbix.HostGroups{hostGroup})
+	})
 }
 
 func resourceZabbixHostGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return api.HostGroupsDeleteByIds([]string{d.Id()})
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.HostGroupsDeleteByIds([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "Host group", d.Id())
 }
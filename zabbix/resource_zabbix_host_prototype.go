@@ -0,0 +1,376 @@
+package zabbix
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+// hostPrototype is a host prototype returned by hostprototype.get
+type hostPrototype struct {
+	HostID        string `json:"hostid"`
+	Host          string `json:"host"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	InventoryMode string `json:"inventory_mode"`
+	// Inventory holds the inventory mode before Zabbix 4.4
+	Inventory struct {
+		InventoryMode string `json:"inventory_mode"`
+	} `json:"inventory"`
+
+	DiscoveryRule struct {
+		ItemID string `json:"itemid"`
+	} `json:"discoveryRule"`
+	GroupLinks []struct {
+		GroupID string `json:"groupid"`
+	} `json:"groupLinks"`
+	GroupPrototypes []struct {
+		Name string `json:"name"`
+	} `json:"groupPrototypes"`
+	Templates []struct {
+		TemplateID string `json:"templateid"`
+		Host       string `json:"host"`
+	} `json:"templates"`
+	Macros           zabbix.Macros   `json:"macros"`
+	Tags             zabbix.Tags     `json:"tags"`
+	CustomInterfaces string          `json:"custom_interfaces"`
+	Interfaces       []hostInterface `json:"interfaces"`
+}
+
+func resourceZabbixHostPrototype() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceZabbixHostPrototypeCreate,
+		Read:   resourceZabbixHostPrototypeRead,
+		Update: resourceZabbixHostPrototypeUpdate,
+		Delete: resourceZabbixHostPrototypeDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: resourceTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"rule_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the low level discovery rule that the host prototype belongs to.",
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Technical name of the host prototype, must contain LLD macros.",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Visible name of the host prototype.",
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(int)
+					if v < 0 || v > 1 {
+						errs = append(errs, fmt.Errorf("%q, must be between 0 and 1 inclusive, got %d", key, v))
+					}
+					return
+				},
+				Description: "Status of the discovered hosts: 0 (monitored) or 1 (unmonitored).",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Required:    true,
+				MinItems:    1,
+				Description: "IDs of the existing host groups the discovered hosts are added to.",
+			},
+			"group_prototypes": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Names of the host groups created for the discovered hosts, must contain LLD macros.",
+			},
+			"templates": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Technical names of the templates linked to the discovered hosts.",
+			},
+			"macro": &schema.Schema{
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "User macros of the discovered hosts (Zabbix >=5.0).",
+			},
+			"tags": &schema.Schema{
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Tags of the discovered hosts (Zabbix >=5.4).",
+			},
+			"interfaces": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        interfaceSchema,
+				Optional:    true,
+				Description: "Custom interfaces of the discovered hosts, the interfaces of the parent host are used when empty (Zabbix >=5.2).",
+			},
+			"inventory_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "disabled",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if _, ok := HostInventoryModes[val.(string)]; !ok {
+						errs = append(errs, fmt.Errorf("%q must be one of disabled, manual or automatic, got %q", key, val))
+					}
+					return
+				},
+				Description: "Inventory mode of the discovered hosts: disabled, manual or automatic.",
+			},
+		},
+		CustomizeDiff: customdiff.All(
+			validateCapabilities(map[string]capability{
+				"macro":      capabilityHostPrototypeMacros,
+				"tags":       capabilityHostPrototypeTags,
+				"interfaces": capabilityHostPrototypeInterfaces,
+			}),
+			validateHostInterfaces,
+		),
+	}
+}
+
+func createHostPrototypeObj(d *schema.ResourceData, meta interface{}) (zabbix.Params, error) {
+	m := meta.(*providerMeta)
+
+	groupLinks := []map[string]string{}
+	for _, id := range d.Get("group_ids").(*schema.Set).List() {
+		groupLinks = append(groupLinks, map[string]string{"groupid": id.(string)})
+	}
+
+	groupPrototypes := []map[string]string{}
+	for _, name := range d.Get("group_prototypes").(*schema.Set).List() {
+		groupPrototypes = append(groupPrototypes, map[string]string{"name": name.(string)})
+	}
+
+	templates, err := getTemplates(d, m.api)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = zabbix.TemplateIDs{}
+	}
+
+	hostPrototype := zabbix.Params{
+		"host":            d.Get("host").(string),
+		"status":          fmt.Sprintf("%d", d.Get("status").(int)),
+		"groupLinks":      groupLinks,
+		"groupPrototypes": groupPrototypes,
+		"templates":       templates,
+	}
+
+	if name := d.Get("name").(string); name != "" {
+		hostPrototype["name"] = name
+	}
+
+	// the inventory mode is a field of the inventory before Zabbix 4.4
+	inventoryMode := HostInventoryModes[d.Get("inventory_mode").(string)]
+	if m.supports(capabilityInventoryModeField) {
+		hostPrototype["inventory_mode"] = inventoryMode
+	} else {
+		hostPrototype["inventory"] = map[string]string{"inventory_mode": inventoryMode}
+	}
+
+	if m.supports(capabilityHostPrototypeMacros) {
+		macros := createZabbixMacro(d)
+		if macros == nil {
+			macros = zabbix.Macros{}
+		}
+		hostPrototype["macros"] = macros
+	}
+
+	if m.supports(capabilityHostPrototypeTags) {
+		tags := createZabbixTag(d)
+		if tags == nil {
+			tags = zabbix.Tags{}
+		}
+		hostPrototype["tags"] = tags
+	}
+
+	if m.supports(capabilityHostPrototypeInterfaces) {
+		interfaces, err := createInterfacesObj(d)
+		if err != nil {
+			return nil, err
+		}
+		// the interfaces of a host prototype are replaced on every update
+		for i := range interfaces {
+			interfaces[i].InterfaceID = ""
+		}
+		hostPrototype["custom_interfaces"] = "0"
+		if len(interfaces) > 0 {
+			hostPrototype["custom_interfaces"] = "1"
+			hostPrototype["interfaces"] = interfaces
+		}
+	}
+
+	return hostPrototype, nil
+}
+
+func resourceZabbixHostPrototypeCreate(d *schema.ResourceData, meta interface{}) error {
+	hostPrototype, err := createHostPrototypeObj(d, meta)
+	if err != nil {
+		return err
+	}
+	hostPrototype["ruleid"] = d.Get("rule_id").(string)
+
+	return createRetry(d, meta, createHostPrototype, hostPrototype, resourceZabbixHostPrototypeRead)
+}
+
+func resourceZabbixHostPrototypeRead(d *schema.ResourceData, meta interface{}) error {
+	m := meta.(*providerMeta)
+
+	params := zabbix.Params{
+		"output":                []string{"hostid", "host", "name", "status", "custom_interfaces"},
+		"hostids":               d.Id(),
+		"selectDiscoveryRule":   []string{"itemid"},
+		"selectGroupLinks":      []string{"groupid"},
+		"selectGroupPrototypes": []string{"name"},
+		"selectTemplates":       []string{"templateid", "host"},
+	}
+	if m.supports(capabilityInventoryModeField) {
+		params["output"] = append(params["output"].([]string), "inventory_mode")
+	} else {
+		params["selectInventory"] = []string{"inventory_mode"}
+	}
+	if m.supports(capabilityHostPrototypeMacros) {
+		params["selectMacros"] = []string{"macro", "value"}
+	}
+	if m.supports(capabilityHostPrototypeTags) {
+		params["selectTags"] = []string{"tag", "value"}
+	}
+	if m.supports(capabilityHostPrototypeInterfaces) {
+		params["selectInterfaces"] = "extend"
+	}
+
+	var hostPrototypes []hostPrototype
+	err := m.api.CallWithErrorParse("hostprototype.get", params, &hostPrototypes)
+	if err != nil {
+		return readError(d, err, "Failed to read host prototype %s", d.Id())
+	}
+	if len(hostPrototypes) == 0 {
+		return readError(d, newNotFoundError("Host prototype %s doesn't exist", d.Id()), "Failed to read host prototype %s", d.Id())
+	}
+	if len(hostPrototypes) != 1 {
+		return fmt.Errorf("Expected one host prototype and got : %d", len(hostPrototypes))
+	}
+	hostPrototype := hostPrototypes[0]
+
+	log.Printf("[DEBUG] Host prototype name is %s", hostPrototype.Host)
+
+	d.Set("rule_id", hostPrototype.DiscoveryRule.ItemID)
+	d.Set("host", hostPrototype.Host)
+	d.Set("name", hostPrototype.Name)
+	d.Set("status", atoiOrZero(hostPrototype.Status))
+	inventoryMode := hostPrototype.InventoryMode
+	if !m.supports(capabilityInventoryModeField) {
+		inventoryMode = hostPrototype.Inventory.InventoryMode
+	}
+	if inventoryMode == "" {
+		inventoryMode = HostInventoryModes["disabled"]
+	}
+	d.Set("inventory_mode", hostInventoryModeName(inventoryMode))
+
+	groupIDs := make([]string, len(hostPrototype.GroupLinks))
+	for i, g := range hostPrototype.GroupLinks {
+		groupIDs[i] = g.GroupID
+	}
+	d.Set("group_ids", groupIDs)
+
+	groupPrototypes := make([]string, len(hostPrototype.GroupPrototypes))
+	for i, g := range hostPrototype.GroupPrototypes {
+		groupPrototypes[i] = g.Name
+	}
+	d.Set("group_prototypes", groupPrototypes)
+
+	templateNames := make([]string, len(hostPrototype.Templates))
+	for i, t := range hostPrototype.Templates {
+		templateNames[i] = t.Host
+	}
+	d.Set("templates", templateNames)
+
+	h := &zabbix.Host{UserMacros: hostPrototype.Macros, Tags: hostPrototype.Tags}
+
+	if m.supports(capabilityHostPrototypeMacros) {
+		terraformMacros, err := createTerraformMacroHost(h)
+		if err != nil {
+			return err
+		}
+		d.Set("macro", terraformMacros)
+	}
+
+	if m.supports(capabilityHostPrototypeTags) {
+		terraformTags, err := createTerraformTag(h)
+		if err != nil {
+			return err
+		}
+		d.Set("tags", terraformTags)
+	}
+
+	if m.supports(capabilityHostPrototypeInterfaces) {
+		interfaces := []hostInterface{}
+		if hostPrototype.CustomInterfaces == "1" {
+			interfaces = hostPrototype.Interfaces
+		}
+		d.Set("interfaces", flattenHostInterfaces(interfaces))
+	}
+
+	return nil
+}
+
+func resourceZabbixHostPrototypeUpdate(d *schema.ResourceData, meta interface{}) error {
+	hostPrototype, err := createHostPrototypeObj(d, meta)
+	if err != nil {
+		return err
+	}
+	hostPrototype["hostid"] = d.Id()
+
+	return createRetry(d, meta, updateHostPrototype, hostPrototype, resourceZabbixHostPrototypeRead)
+}
+
+func resourceZabbixHostPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).api
+
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := api.CallWithError("hostprototype.delete", []string{d.Id()})
+		return err
+	})
+	return ignoreNotFound(err, "Host prototype", d.Id())
+}
+
+func createHostPrototype(hostPrototype interface{}, api *zabbix.API) (id string, err error) {
+	var result struct {
+		HostIDs []string `json:"hostids"`
+	}
+
+	err = api.CallWithErrorParse("hostprototype.create", hostPrototype, &result)
+	if err != nil {
+		return
+	}
+	if len(result.HostIDs) != 1 {
+		err = fmt.Errorf("Expected one host prototype to be created, got %d", len(result.HostIDs))
+		return
+	}
+	id = result.HostIDs[0]
+	return
+}
+
+func updateHostPrototype(hostPrototype interface{}, api *zabbix.API) (id string, err error) {
+	_, err = api.CallWithError("hostprototype.update", hostPrototype)
+	if err != nil {
+		return
+	}
+	id = hostPrototype.(zabbix.Params)["hostid"].(string)
+	return
+}
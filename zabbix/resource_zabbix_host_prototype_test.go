@@ -0,0 +1,112 @@
+package zabbix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/nzolot/go-zabbix-api"
+)
+
+func TestAccZabbixHostPrototype_Basic(t *testing.T) {
+	strID := acctest.RandString(5)
+	groupName := fmt.Sprintf("host_group_%s", strID)
+	templateName := fmt.Sprintf("template_%s", strID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostPrototypeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostPrototypeConfig(groupName, templateName, "{#HOST.NAME}", 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "host", "{#HOST.ID}"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "name", "{#HOST.NAME}"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "status", "0"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "group_ids.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "group_prototypes.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "inventory_mode", "manual"),
+					resource.TestCheckResourceAttrPair("zabbix_host_prototype.host_prototype_test", "rule_id", "zabbix_lld_rule.lld_rule_test", "id"),
+				),
+			},
+			{
+				Config: testAccZabbixHostPrototypeConfig(groupName, templateName, "VM {#HOST.NAME}", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "name", "VM {#HOST.NAME}"),
+					resource.TestCheckResourceAttr("zabbix_host_prototype.host_prototype_test", "status", "1"),
+				),
+			},
+			{
+				ResourceName:      "zabbix_host_prototype.host_prototype_test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckZabbixHostPrototypeDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).api
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_host_prototype" {
+			continue
+		}
+
+		var hostPrototypes []hostPrototype
+		err := api.CallWithErrorParse("hostprototype.get", zabbix.Params{
+			"output":  []string{"hostid"},
+			"hostids": rs.Primary.ID,
+		}, &hostPrototypes)
+		if err != nil {
+			return err
+		}
+		if len(hostPrototypes) != 0 {
+			return fmt.Errorf("Host prototype still exist %s", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccZabbixHostPrototypeConfig(groupName, templateName, name string, status int) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host_group" "zabbix" {
+			name = "host group test %s"
+		}
+
+		resource "zabbix_template" "template_test" {
+			host = "%s"
+			groups = ["${zabbix_host_group.zabbix.name}"]
+			name = "display name for template_test %s"
+		}
+
+		resource "zabbix_lld_rule" "lld_rule_test" {
+			delay = 60
+			host_id = zabbix_template.template_test.id
+			interface_id = "0"
+			key = "key.hosts"
+			name = "test_low_level_discovery_rule"
+			type = 2
+			filter {
+				condition {
+					macro = "{#HOST.ID}"
+					value = ".*"
+				}
+				eval_type = 0
+			}
+		}
+
+		resource "zabbix_host_prototype" "host_prototype_test" {
+			rule_id = zabbix_lld_rule.lld_rule_test.id
+			host = "{#HOST.ID}"
+			name = "%s"
+			status = %d
+			group_ids = [zabbix_host_group.zabbix.id]
+			group_prototypes = ["discovered {#HOST.GROUP}"]
+			inventory_mode = "manual"
+		}
+	`, groupName, templateName, templateName, name, status)
+}
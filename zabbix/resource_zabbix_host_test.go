@@ -1,7 +1,9 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
@@ -39,8 +41,216 @@ func TestAccZabbixHost_Basic(t *testing.T) {
 	})
 }
 
+func TestAccZabbixHost_InterfaceUpdate(t *testing.T) {
+	var firstHost, updatedHost zabbix.Host
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostInterfaceConfig(host, hostGroup, "10050"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixHostExists("zabbix_host.zabbix1", &firstHost),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.port", "10050"),
+				),
+			},
+			{
+				Config: testAccZabbixHostInterfaceConfig(host, hostGroup, "10051"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixHostExists("zabbix_host.zabbix1", &updatedHost),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.port", "10051"),
+					func(*terraform.State) error {
+						if firstHost.HostID != updatedHost.HostID {
+							return fmt.Errorf("Host was recreated, got id %s, expected %s", updatedHost.HostID, firstHost.HostID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccZabbixHost_Disappears(t *testing.T) {
+	var getHost zabbix.Host
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	name := fmt.Sprintf("name_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostConfig(host, name, hostGroup),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixHostExists("zabbix_host.zabbix1", &getHost),
+					func(*terraform.State) error {
+						api := testAccProvider.Meta().(*providerMeta).api
+						return api.HostsDeleteByIds([]string{getHost.HostID})
+					},
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccZabbixHost_DisableOnDestroy(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDecommissioned(host, "Discovered hosts", "decommissioned_at"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostDisableOnDestroyConfig(host, hostGroup),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "on_destroy", "disable_and_move"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccZabbixHost_WaitForAvailabilityTimeout(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccZabbixHostWaitForAvailabilityConfig(host, hostGroup),
+				ExpectError: regexp.MustCompile("didn't become available within 20s: agent is"),
+			},
+		},
+	})
+}
+
+// testAccCheckZabbixHostDecommissioned check the host was disabled, moved and
+// tagged instead of deleted, then delete it
+func testAccCheckZabbixHostDecommissioned(host, archiveGroup, tag string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		api := testAccProvider.Meta().(*providerMeta).api
+
+		var hosts []struct {
+			HostID string        `json:"hostid"`
+			Status string        `json:"status"`
+			Groups []zabbixGroup `json:"groups"`
+			Tags   zabbix.Tags   `json:"tags"`
+		}
+		err := api.CallWithErrorParse("host.get", zabbix.Params{
+			"output":       []string{"hostid", "status"},
+			"filter":       map[string]interface{}{"host": host},
+			"selectGroups": []string{"groupid", "name"},
+			"selectTags":   []string{"tag", "value"},
+		}, &hosts)
+		if err != nil {
+			return err
+		}
+		if len(hosts) != 1 {
+			return fmt.Errorf("Expected the host %s to be kept, got %d hosts", host, len(hosts))
+		}
+		defer api.HostsDeleteByIds([]string{hosts[0].HostID})
+
+		if hosts[0].Status != "1" {
+			return fmt.Errorf("Expected the host to be disabled, got status %s", hosts[0].Status)
+		}
+		if len(hosts[0].Groups) != 1 || hosts[0].Groups[0].Name != archiveGroup {
+			return fmt.Errorf("Expected the host to be moved to %s, got %v", archiveGroup, hosts[0].Groups)
+		}
+		for _, t := range hosts[0].Tags {
+			if t.TagName == tag && t.Value != "" {
+				return nil
+			}
+		}
+		return fmt.Errorf("Expected the host to be tagged with %s, got %v", tag, hosts[0].Tags)
+	}
+}
+
+func TestCheckMainInterfaces(t *testing.T) {
+	valid := []hostInterface{
+		{Type: "1", Main: "1"},
+		{Type: "1", Main: "0"},
+		{Type: "2", Main: "1"},
+	}
+	if err := checkMainInterfaces(valid); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	noMain := []hostInterface{
+		{Type: "1", Main: "1"},
+		{Type: "2", Main: "0"},
+	}
+	if err := checkMainInterfaces(noMain); err == nil {
+		t.Fatal("Expected an error without main snmp interface")
+	}
+
+	twoMains := []hostInterface{
+		{Type: "1", Main: "1"},
+		{Type: "1", Main: "1"},
+	}
+	if err := checkMainInterfaces(twoMains); err == nil {
+		t.Fatal("Expected an error with two main agent interfaces")
+	}
+}
+
+func TestSortHostInterfaces(t *testing.T) {
+	interfaces := []hostInterface{
+		{InterfaceID: "1"},
+		{InterfaceID: "2"},
+		{InterfaceID: "3"},
+	}
+	sorted := sortHostInterfaces(interfaces, []string{"3", "1"})
+
+	for i, expected := range []string{"3", "1", "2"} {
+		if sorted[i].InterfaceID != expected {
+			t.Fatalf("Got interface %s at %d, expected %s", sorted[i].InterfaceID, i, expected)
+		}
+	}
+}
+
+func TestHostInterfaceDetails(t *testing.T) {
+	var interfaces []hostInterface
+	body := `[
+		{"interfaceid":"1","type":"1","main":"1","details":[]},
+		{"interfaceid":"2","type":"2","main":"1","details":{"version":"2","bulk":"1","community":"{$SNMP_COMMUNITY}"}}
+	]`
+	if err := json.Unmarshal([]byte(body), &interfaces); err != nil {
+		t.Fatal(err)
+	}
+
+	flattened := flattenHostInterfaces(interfaces)
+	if details := flattened[0].(map[string]interface{})["details"].([]interface{}); len(details) != 0 {
+		t.Fatalf("Got details %v on an agent interface", details)
+	}
+
+	details := flattened[1].(map[string]interface{})["details"].([]interface{})
+	if len(details) != 1 {
+		t.Fatalf("Got %d details on a snmp interface, expected 1", len(details))
+	}
+	expanded := expandHostInterfaceDetails(details[0].(map[string]interface{}))
+	if *expanded != *interfaces[1].Details {
+		t.Fatalf("Got details %+v after a round trip, expected %+v", *expanded, *interfaces[1].Details)
+	}
+}
+
 func testAccCheckZabbixHostDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_host" {
@@ -77,6 +287,24 @@ func testAccZabbixHostConfig(host string, name string, hostGroup string) string
 	)
 }
 
+func testAccZabbixHostInterfaceConfig(host string, hostGroup string, port string) string {
+	return fmt.Sprintf(`
+	  	resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+		  		ip = "127.0.0.1"
+				main = true
+				port = "%s"
+			}
+			groups = ["${zabbix_host_group.zabbix.name}"]
+	  	}
+
+	  	resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+	  	}`, host, port, hostGroup,
+	)
+}
+
 func testAccCheckZabbixHostExists(resource string, host *zabbix.Host) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
 		rs, ok := state.RootModule().Resources[resource]
@@ -87,7 +315,7 @@ func testAccCheckZabbixHostExists(resource string, host *zabbix.Host) resource.T
 			return fmt.Errorf("No record ID id set")
 		}
 
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).api
 		getHost, err := api.HostGetByID(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -99,7 +327,7 @@ func testAccCheckZabbixHostExists(resource string, host *zabbix.Host) resource.T
 
 func testAccCheckZabbixHostAttributes(host *zabbix.Host, want zabbix.Host, groupNames []string, templateNames []string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).api
 
 		if host.Host != want.Host {
 			return fmt.Errorf("Got host name: %q, expected: %q", host.Host, want.Host)
@@ -157,3 +385,44 @@ func containTemplate(templateNames zabbix.Templates, name string) bool {
 	}
 	return false
 }
+
+func testAccZabbixHostDisableOnDestroyConfig(host string, hostGroup string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip = "127.0.0.1"
+				main = true
+			}
+			groups = ["${zabbix_host_group.zabbix.name}"]
+			on_destroy = "disable_and_move"
+			archive_group = "Discovered hosts"
+			decommission_tag = "decommissioned_at"
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}`, host, hostGroup,
+	)
+}
+
+func testAccZabbixHostWaitForAvailabilityConfig(host string, hostGroup string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip = "192.0.2.1"
+				main = true
+			}
+			groups = ["${zabbix_host_group.zabbix.name}"]
+			wait_for_availability {
+				interface_types = ["agent"]
+				timeout = "20s"
+			}
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}`, host, hostGroup,
+	)
+}
@@ -47,6 +47,7 @@ func resourceZabbixHttpTest() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -168,11 +169,11 @@ func createStepsObj(d *schema.ResourceData) (zabbix.Steps, error) {
 }
 
 func resourceZabbixHttpTestRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	httptest, err := api.HttpTestGetByID(d.Id())
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read web check %s", d.Id())
 	}
 
 	d.Set("name", httptest.Name)
@@ -211,7 +212,10 @@ func updateHttpTest(httptest interface{}, api *zabbix.API) (id string, err error
 
 // Delete
 func resourceZabbixHttpTestDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return api.HttpTestsDeleteByIds([]string{d.Id()})
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.HttpTestsDeleteByIds([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "Web check", d.Id())
 }
@@ -3,7 +3,6 @@ package zabbix
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
@@ -19,6 +18,7 @@ func resourceZabbixItem() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"delay": &schema.Schema{
 				Type:     schema.TypeString,
@@ -122,7 +122,7 @@ func resourceZabbixItem() *schema.Resource {
 				Type:        schema.TypeMap,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "Tags for item. Support in Zabbix >=6.0",
+				Description: "Tags for item. Support in Zabbix >=5.4",
 			},
 			"preprocessing": &schema.Schema{
 				Type:        schema.TypeList,
@@ -143,6 +143,12 @@ func resourceZabbixItem() *schema.Resource {
 				Description: "Status of the item.",
 			},
 		},
+		CustomizeDiff: validateCapabilities(map[string]capability{
+			"tags":          capabilityItemTags,
+			"preprocessing": capabilityItemPreprocessing,
+			"data_type":     capabilityItemDataTypeDelta,
+			"delta":         capabilityItemDataTypeDelta,
+		}),
 	}
 }
 
@@ -178,11 +184,11 @@ func resourceZabbixItemCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixItemRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	item, err := api.ItemGetByID(d.Id())
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read item %s", d.Id())
 	}
 
 	d.Set("delay", item.Delay)
@@ -213,11 +219,11 @@ func resourceZabbixItemRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixItemExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.ItemGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Item with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -235,9 +241,9 @@ func resourceZabbixItemUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixItemDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return deleteRetry(d.Id(), getItemParentID, api.ItemsDeleteIDs, api)
+	return deleteRetry(d.Id(), getItemParentID, api.ItemsDeleteIDs, api, d.Timeout(schema.TimeoutDelete))
 }
 
 func getItemParentID(api *zabbix.API, id string) (string, error) {
@@ -249,6 +255,9 @@ func getItemParentID(api *zabbix.API, id string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("%s, with item %s", err.Error(), id)
 	}
+	if len(items) == 0 {
+		return "", newNotFoundError("Item %s doesn't exist", id)
+	}
 	if len(items) != 1 {
 		return "", fmt.Errorf("Expected one item and got %d items", len(items))
 	}
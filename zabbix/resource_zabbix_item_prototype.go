@@ -3,7 +3,6 @@ package zabbix
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
@@ -19,6 +18,7 @@ func resourceZabbixItemPrototype() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"delay": &schema.Schema{
 				Type:     schema.TypeString,
@@ -126,7 +126,7 @@ func resourceZabbixItemPrototype() *schema.Resource {
 				Type:        schema.TypeMap,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "Tags for item. Support in Zabbix >=6.0",
+				Description: "Tags for item. Support in Zabbix >=5.4",
 			},
 			"preprocessing": &schema.Schema{
 				Type:        schema.TypeList,
@@ -147,6 +147,12 @@ func resourceZabbixItemPrototype() *schema.Resource {
 				Description: "Status of the item.",
 			},
 		},
+		CustomizeDiff: validateCapabilities(map[string]capability{
+			"tags":          capabilityItemTags,
+			"preprocessing": capabilityItemPreprocessing,
+			"data_type":     capabilityItemDataTypeDelta,
+			"delta":         capabilityItemDataTypeDelta,
+		}),
 	}
 }
 
@@ -176,7 +182,7 @@ func createItemPrototypeObject(d *schema.ResourceData, api *zabbix.API) (*zabbix
 }
 
 func resourceZabbixItemPrototypeCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	item, err := createItemPrototypeObject(d, api)
 	if err != nil {
@@ -187,7 +193,7 @@ func resourceZabbixItemPrototypeCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	items, err := api.ItemPrototypesGet(zabbix.Params{
 		"itemids":             d.Id(),
@@ -195,7 +201,10 @@ func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) e
 		"selectDiscoveryRule": "extend",
 	})
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read item prototype %s", d.Id())
+	}
+	if len(items) == 0 {
+		return readError(d, newNotFoundError("Item prototype %s doesn't exist", d.Id()), "Failed to read item prototype %s", d.Id())
 	}
 	if len(items) != 1 {
 		return fmt.Errorf("Expected one item prototype and got : %d ", len(items))
@@ -231,11 +240,11 @@ func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceZabbixItemPrototypeExist(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.ItemPrototypeGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Item prototype with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -245,7 +254,7 @@ func resourceZabbixItemPrototypeExist(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	item, err := createItemPrototypeObject(d, api)
 	if err != nil {
@@ -258,9 +267,9 @@ func resourceZabbixItemPrototypeUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return deleteRetry(d.Id(), getItemPrototypeParentID, api.ItemPrototypesDeleteIDs, api)
+	return deleteRetry(d.Id(), getItemPrototypeParentID, api.ItemPrototypesDeleteIDs, api, d.Timeout(schema.TimeoutDelete))
 }
 
 func getItemPrototypeParentID(api *zabbix.API, id string) (string, error) {
@@ -272,6 +281,9 @@ func getItemPrototypeParentID(api *zabbix.API, id string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("%s, with item %s", err.Error(), id)
 	}
+	if len(items) == 0 {
+		return "", newNotFoundError("Item prototype %s doesn't exist", id)
+	}
 	if len(items) != 1 {
 		return "", fmt.Errorf("Expected one item and got %d items", len(items))
 	}
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/nzolot/go-zabbix-api"
 )
 
 func TestAccZabbixItemPrototype_Basic(t *testing.T) {
@@ -47,7 +46,7 @@ func TestAccZabbixItemPrototype_Basic(t *testing.T) {
 }
 
 func testAccCheckZabbixItemPrototypeDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_item_prototype" {
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/nzolot/go-zabbix-api"
 )
 
 func TestAccZabbixItem_Basic(t *testing.T) {
@@ -125,7 +124,7 @@ func testAccZabbixItemExists(resource string) resource.TestCheckFunc {
 }
 
 func testAccCheckZabbixItemDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_item" {
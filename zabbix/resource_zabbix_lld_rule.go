@@ -3,7 +3,6 @@ package zabbix
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/nzolot/go-zabbix-api"
@@ -19,6 +18,7 @@ func resourceZabbixLLDRule() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"delay": &schema.Schema{
 				Type:     schema.TypeString,
@@ -53,15 +53,19 @@ func resourceZabbixLLDRule() *schema.Resource {
 				Type:        schema.TypeList,
 				Elem:        itemPreprocessingSchema,
 				Optional:    true,
-				Description: "Item preprocessing options. Support in Zabbix >=4.0 https://www.zabbix.com/documentation/current/en/manual/api/reference/item/object#item-preprocessing",
+				Description: "LLD rule preprocessing options. Support in Zabbix >=4.2 https://www.zabbix.com/documentation/current/en/manual/api/reference/discoveryrule/object#lld-rule-preprocessing",
 			},
 			"lld_macros": &schema.Schema{
 				Type:        schema.TypeList,
 				Elem:        LLDMacroPathsSchema,
 				Optional:    true,
-				Description: "Item preprocessing options. Support in Zabbix >=4.0 https://www.zabbix.com/documentation/current/en/manual/api/reference/item/object#item-preprocessing",
+				Description: "JSONPath of the LLD macros. Support in Zabbix >=4.2 https://www.zabbix.com/documentation/current/en/manual/api/reference/discoveryrule/object#lld-macro-path",
 			},
 		},
+		CustomizeDiff: validateCapabilities(map[string]capability{
+			"preprocessing": capabilityLLDPreprocessing,
+			"lld_macros":    capabilityLLDPreprocessing,
+		}),
 	}
 }
 
@@ -113,7 +117,7 @@ func resourceZabbixLLDRuleCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixLLDRuleRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 	params := zabbix.Params{
 		"itemids":             d.Id(),
 		"output":              "extend",
@@ -125,7 +129,10 @@ func resourceZabbixLLDRuleRead(d *schema.ResourceData, meta interface{}) error {
 
 	lldRules, err := api.DiscoveryRulesGet(params)
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read low level discovery rule %s", d.Id())
+	}
+	if len(lldRules) == 0 {
+		return readError(d, newNotFoundError("Low level discovery rule %s doesn't exist", d.Id()), "Failed to read low level discovery rule %s", d.Id())
 	}
 	if len(lldRules) != 1 {
 		return fmt.Errorf("Expected one low level discovery rule with id %s and got %d rules", d.Id(), len(lldRules))
@@ -162,11 +169,11 @@ func resourceZabbixLLDRuleRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixLLDRuleExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.DiscoveryRulesGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] LLD rule with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -183,10 +190,12 @@ func resourceZabbixLLDRuleUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixLLDRuleDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	err := api.DiscoveryRulesDeletesByIDs([]string{d.Id()})
-	return err
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.DiscoveryRulesDeletesByIDs([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "LLD rule", d.Id())
 }
 
 func createLLDRuleObject(d *schema.ResourceData) zabbix.LLDRule {
@@ -17,6 +17,7 @@ func resourceZabbixLLDRuleLink() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"lld_rule_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -71,17 +72,17 @@ func resourceZabbixLLDRuleLinkCreate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceZabbixLLDRuleLinkRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	itemsTerraform, err := getTerraformTemplateItemPrototypes(d, api)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read the objects of low level discovery rule %s", d.Get("lld_rule_id").(string))
 	}
 	d.Set("item_prototype", itemsTerraform)
 
 	triggersTerraform, err := getTerraformTemplateTriggerPrototypes(d, api)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read the objects of low level discovery rule %s", d.Get("lld_rule_id").(string))
 	}
 	d.Set("trigger_prototype", triggersTerraform)
 
@@ -90,11 +91,12 @@ func resourceZabbixLLDRuleLinkRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceZabbixLLDRuleLinkExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	return true, nil
+	// the link has the id of its LLD rule and goes away with it
+	return resourceZabbixLLDRuleExists(d, meta)
 }
 
 func resourceZabbixLLDRuleLinkUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	err := updateZabbixTemplateItemPrototypes(d, api)
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/nzolot/go-zabbix-api"
 )
 
 func TestAccZabbixLLDRule_Basic(t *testing.T) {
@@ -54,7 +53,7 @@ func TestAccZabbixLLDRule_Basic(t *testing.T) {
 }
 
 func testAccCheckZabbixLLDRuleDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_lld_rule" {
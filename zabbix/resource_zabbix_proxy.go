@@ -14,6 +14,13 @@ func resourceZabbixProxy() *schema.Resource {
 		Read:   resourceZabbixProxyRead,
 		Update: resourceZabbixProxyUpdate,
 		Delete: resourceZabbixProxyDelete,
+		Importer: &schema.ResourceImporter{
+			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				// the name of the proxies is the name field since Zabbix 7.0
+				return importByIDOrName("proxy", "proxy.get", "proxyid", proxyNameField(meta.(*providerMeta)))(d, meta)
+			},
+		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"host": &schema.Schema{
 				Type:        schema.TypeString,
@@ -31,9 +38,11 @@ func resourceZabbixProxy() *schema.Resource {
 				Optional: true,
 			},
 			"interfaces": &schema.Schema{
-				Type:     schema.TypeList,
-				Elem:     proxyInterfaceSchema,
-				Optional: true,
+				Type:        schema.TypeList,
+				Elem:        proxyInterfaceSchema,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Interface of a passive proxy, changing it recreates the proxy.",
 			},
 			"tls_connect": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -70,7 +79,17 @@ func resourceZabbixProxy() *schema.Resource {
 				Optional: true,
 			},
 		},
+		CustomizeDiff: validateProxyInterfaces,
+	}
+}
+
+// validateProxyInterfaces reject the interfaces of active proxies, only the
+// passive proxies are polled through an interface
+func validateProxyInterfaces(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("passive").(bool) && d.Get("interfaces.#").(int) > 0 {
+		return errors.New("interfaces can only be set on passive proxies")
 	}
+	return nil
 }
 
 func createProxyInterfacesObj(d *schema.ResourceData) (zabbix.ProxyInterfaces, error) {
@@ -153,7 +172,7 @@ func createProxyObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Proxy, err
 }
 
 func resourceZabbixProxyCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	proxy, err := createProxyObj(d, api)
 
@@ -163,7 +182,9 @@ func resourceZabbixProxyCreate(d *schema.ResourceData, meta interface{}) error {
 
 	proxys := zabbix.Proxies{*proxy}
 
-	err = api.ProxiesCreate(proxys)
+	err = apiRetry(d.Timeout(schema.TimeoutCreate), func() error {
+		return api.ProxiesCreate(proxys)
+	})
 
 	if err != nil {
 		return err
@@ -178,16 +199,17 @@ func resourceZabbixProxyCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixProxyRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	log.Printf("Will read proxy with id %s", d.Get("proxyid").(string))
+	log.Printf("Will read proxy with id %s", d.Id())
 
-	proxy, err := api.ProxyGetById(d.Get("proxyid").(string))
+	proxy, err := api.ProxyGetById(d.Id())
 
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read proxy %s", d.Id())
 	}
 
+	d.Set("proxyid", d.Id())
 	d.Set("host", proxy.Host)
 	d.Set("description", proxy.Description)
 
@@ -195,15 +217,63 @@ func resourceZabbixProxyRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("tls_accept", proxy.TlsAccept)
 	d.Set("tls_psk", proxy.TlsPsk)
 	d.Set("tls_psk_identity", proxy.TlsPskIdentity)
-	d.Set("proxy_address", proxy.Address)
+	d.Set("address", proxy.Address)
 
 	d.Set("passive", proxy.Status == 6)
 
+	interfaces, err := getProxyInterfaces(api, d.Id())
+
+	if err != nil {
+		return describeAPIError(err, "Failed to read interface of proxy %s", d.Id())
+	}
+
+	// useip is derived from ip when the interface is sent, keep the configured one
+	if len(interfaces) == 1 && d.Get("interfaces.#").(int) == 1 {
+		interfaces[0].(map[string]interface{})["useip"] = d.Get("interfaces.0.useip").(string)
+	}
+
+	d.Set("interfaces", interfaces)
+
 	return nil
 }
 
+// getProxyInterfaces return the interface of a passive proxy in the form of the
+// state, active proxies have none
+func getProxyInterfaces(api *zabbix.API, id string) ([]interface{}, error) {
+	var proxies []struct {
+		// an empty array for the active proxies
+		Interface interface{} `json:"interface"`
+	}
+
+	err := api.CallWithErrorParse("proxy.get", zabbix.Params{
+		"output":          []string{"proxyid"},
+		"proxyids":        id,
+		"selectInterface": []string{"dns", "ip", "port", "useip"},
+	}, &proxies)
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) != 1 {
+		return nil, newNotFoundError("Proxy %s doesn't exist", id)
+	}
+
+	proxyInterface, ok := proxies[0].Interface.(map[string]interface{})
+	if !ok {
+		return []interface{}{}, nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"dns":   fmt.Sprintf("%v", proxyInterface["dns"]),
+			"ip":    fmt.Sprintf("%v", proxyInterface["ip"]),
+			"port":  fmt.Sprintf("%v", proxyInterface["port"]),
+			"useip": fmt.Sprintf("%v", proxyInterface["useip"]),
+		},
+	}, nil
+}
+
 func resourceZabbixProxyUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	proxy, err := createProxyObj(d, api)
 
@@ -213,13 +283,15 @@ func resourceZabbixProxyUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	proxy.ProxyId = d.Id()
 
-	//interfaces can't be updated, changes will trigger recreate
+	//interfaces can't be updated, changes trigger a recreate
 	//sending previous values will also fail the update
 	proxy.Interfaces = nil
 
 	proxys := zabbix.Proxies{*proxy}
 
-	err = api.ProxiesUpdate(proxys)
+	err = apiRetry(d.Timeout(schema.TimeoutUpdate), func() error {
+		return api.ProxiesUpdate(proxys)
+	})
 
 	if err != nil {
 		return err
@@ -231,7 +303,10 @@ func resourceZabbixProxyUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixProxyDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return api.ProxiesDeleteByIds([]string{d.Id()})
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.ProxiesDeleteByIds([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "Proxy", d.Id())
 }
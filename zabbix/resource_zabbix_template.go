@@ -19,6 +19,7 @@ func resourceZabbixTemplate() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"host": &schema.Schema{
 				Type:        schema.TypeString,
@@ -26,10 +27,23 @@ func resourceZabbixTemplate() *schema.Resource {
 				Description: "Technical name of the template.",
 			},
 			"groups": &schema.Schema{
+				Type:         schema.TypeSet,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				AtLeastOneOf: []string{"groups", "group_ids"},
+				Description:  "Names of the template groups.",
+			},
+			"group_ids": &schema.Schema{
 				Type:        schema.TypeSet,
 				Elem:        &schema.Schema{Type: schema.TypeString},
-				Required:    true,
-				Description: "ID of the Host Group.",
+				Optional:    true,
+				Description: "IDs of the template groups, not affected by group renames.",
+			},
+			"create_missing_groups": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create the groups which don't exist yet instead of failing.",
 			},
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -69,7 +83,9 @@ func createLinkedTemplate(d *schema.ResourceData) zabbix.Templates {
 	return templates
 }
 
-func createTemplateObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Template, error) {
+func createTemplateObj(d *schema.ResourceData, meta interface{}) (*zabbix.Template, error) {
+	api := meta.(*providerMeta).api
+
 	template := zabbix.Template{
 		Host:            d.Get("host").(string),
 		Name:            d.Get("name").(string),
@@ -77,13 +93,13 @@ func createTemplateObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Templat
 		UserMacros:      createZabbixMacro(d),
 		LinkedTemplates: createLinkedTemplate(d),
 	}
-	hostGroupIDs, err := getHostGroups(d, api)
+	groupIDs, err := getGroupIDs(d, api, templateGroupsAPI(meta.(*providerMeta)))
 	if err != nil {
 		return nil, err
 	}
-	template.Groups = make([]zabbix.HostGroup, len(hostGroupIDs))
-	for i, ID := range hostGroupIDs {
-		template.Groups[i].GroupID = ID.GroupID
+	template.Groups = make([]zabbix.HostGroup, len(groupIDs))
+	for i, ID := range groupIDs {
+		template.Groups[i].GroupID = ID
 	}
 	if template.UserMacros == nil {
 		template.UserMacros = zabbix.Macros{}
@@ -92,9 +108,7 @@ func createTemplateObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Templat
 }
 
 func resourceZabbixTemplateCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	template, err := createTemplateObj(d, api)
+	template, err := createTemplateObj(d, meta)
 	if err != nil {
 		return err
 	}
@@ -103,7 +117,7 @@ func resourceZabbixTemplateCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	params := zabbix.Params{
 		"templateids":  d.Id(),
@@ -112,7 +126,10 @@ func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error
 	}
 	templates, err := api.TemplatesGet(params)
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read template %s", d.Id())
+	}
+	if len(templates) == 0 {
+		return readError(d, newNotFoundError("Template %s doesn't exist", d.Id()), "Failed to read template %s", d.Id())
 	}
 	if len(templates) != 1 {
 		log.Printf("[DEBUG] Expected one template with id %s and got %#v", d.Id(), templates)
@@ -132,20 +149,15 @@ func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error
 	}
 	d.Set("macro", terraformMacros)
 
-	terraformGroups, err := createTerraformTemplateGroup(d, api)
-	if err != nil {
-		return err
-	}
-	d.Set("groups", terraformGroups)
-	return nil
+	return readGroups(d, api, templateGroupsAPI(meta.(*providerMeta)), d.Id())
 }
 
 func resourceZabbixTemplateExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.TemplateGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Template with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -155,9 +167,7 @@ func resourceZabbixTemplateExists(d *schema.ResourceData, meta interface{}) (boo
 }
 
 func resourceZabbixTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	template, err := createTemplateObj(d, api)
+	template, err := createTemplateObj(d, meta)
 	if err != nil {
 		return err
 	}
@@ -168,9 +178,12 @@ func resourceZabbixTemplateUpdate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceZabbixTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return api.TemplatesDeleteByIds([]string{d.Id()})
+	err := apiRetry(d.Timeout(schema.TimeoutDelete), func() error {
+		return api.TemplatesDeleteByIds([]string{d.Id()})
+	})
+	return ignoreNotFound(err, "Template", d.Id())
 }
 
 func createTerraformMacro(template zabbix.Template) (map[string]interface{}, error) {
@@ -193,26 +206,6 @@ func createTerraformMacro(template zabbix.Template) (map[string]interface{}, err
 	return terraformMacros, nil
 }
 
-func createTerraformTemplateGroup(d *schema.ResourceData, api *zabbix.API) ([]string, error) {
-	params := zabbix.Params{
-		"output": "extend",
-		"hostids": []string{
-			d.Id(),
-		},
-	}
-
-	groups, err := api.HostGroupsGet(params)
-	if err != nil {
-		return nil, err
-	}
-
-	groupNames := make([]string, len(groups))
-	for i, g := range groups {
-		groupNames[i] = g.Name
-	}
-	return groupNames, nil
-}
-
 func createTerraformLinkedTemplate(template zabbix.Template) []string {
 	var terraformTemplates []string
 
@@ -17,6 +17,7 @@ func resourceZabbixTemplateLink() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"template_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -91,23 +92,23 @@ func resourceZabbixTemplateLinkCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateLinkRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	itemsTerraform, err := getTerraformTemplateItems(d, api)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read the objects of template %s", d.Get("template_id").(string))
 	}
 	d.Set("item", itemsTerraform)
 
 	triggersTerraform, err := getTerraformTemplateTriggers(d, api)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read the objects of template %s", d.Get("template_id").(string))
 	}
 	d.Set("trigger", triggersTerraform)
 
 	lldRulesTerraform, err := getTerraformTemplateLLDRules(d, api)
 	if err != nil {
-		return err
+		return describeAPIError(err, "Failed to read the objects of template %s", d.Get("template_id").(string))
 	}
 	d.Set("lld_rule", lldRulesTerraform)
 
@@ -116,11 +117,12 @@ func resourceZabbixTemplateLinkRead(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceZabbixTemplateLinkExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	return true, nil
+	// the link has the id of its template and goes away with it
+	return resourceZabbixTemplateExists(d, meta)
 }
 
 func resourceZabbixTemplateLinkUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	err := updateZabbixTemplateItems(d, api)
 	if err != nil {
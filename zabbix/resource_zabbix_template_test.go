@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/nzolot/go-zabbix-api"
 )
 
 func TestAccZabbixTemplate_Basic(t *testing.T) {
@@ -126,7 +125,7 @@ func TestAccZabbixTemplate_linkedTemplate(t *testing.T) {
 }
 
 func testAccCheckZabbixTemplateDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_template" {
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/mcuadros/go-version"
 	"github.com/nzolot/go-zabbix-api"
 )
 
@@ -20,6 +19,7 @@ func resourceZabbixTrigger() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
@@ -84,9 +84,12 @@ func resourceZabbixTrigger() *schema.Resource {
 				Type:        schema.TypeMap,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "Tags for trigger. Support in Zabbix >=6.0",
+				Description: "Tags for trigger. Support in Zabbix >=3.2",
 			},
 		},
+		CustomizeDiff: validateCapabilities(map[string]capability{
+			"tags": capabilityTriggerTags,
+		}),
 	}
 }
 
@@ -97,7 +100,7 @@ func resourceZabbixTriggerCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	params := zabbix.Params{
 		"output":             "extend",
@@ -109,13 +112,16 @@ func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	res, err := api.TriggersGet(params)
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read trigger %s", d.Id())
+	}
+	if len(res) == 0 {
+		return readError(d, newNotFoundError("Trigger %s doesn't exist", d.Id()), "Failed to read trigger %s", d.Id())
 	}
 	if len(res) != 1 {
 		return fmt.Errorf("Expected one result got : %d", len(res))
 	}
 	trigger := res[0]
-	err = getTriggerExpression(&trigger, api)
+	err = getTriggerExpression(&trigger, meta.(*providerMeta))
 	log.Printf("[DEBUG] trigger expression: %s", trigger.Expression)
 	d.Set("description", trigger.Description)
 	d.Set("expression", trigger.Expression)
@@ -152,11 +158,11 @@ func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixTriggerExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.TriggerGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Trigger with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -176,9 +182,9 @@ func resourceZabbixTriggerUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixTriggerDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return deleteRetry(d.Id(), getTriggerParentID, api.TriggersDeleteIDs, api)
+	return deleteRetry(d.Id(), getTriggerParentID, api.TriggersDeleteIDs, api, d.Timeout(schema.TimeoutDelete))
 }
 
 func createTriggerDependencies(d *schema.ResourceData) zabbix.DependencyTriggers {
@@ -206,7 +212,8 @@ func createTriggerObj(d *schema.ResourceData) zabbix.Trigger {
 	}
 }
 
-func getTriggerExpression(trigger *zabbix.Trigger, api *zabbix.API) error {
+func getTriggerExpression(trigger *zabbix.Trigger, m *providerMeta) error {
+	api := m.api
 	for _, function := range trigger.Functions {
 		var item zabbix.Item
 
@@ -228,10 +235,7 @@ func getTriggerExpression(trigger *zabbix.Trigger, api *zabbix.API) error {
 		}
 		idstr := fmt.Sprintf("{%s}", function.FunctionID)
 
-		apiVersion, err := api.Version()
-		log.Printf("[DEBUG] apiVersion: %s", apiVersion)
-
-		if version.Compare(apiVersion, "5.4", ">=") {
+		if m.supports(capabilityNewExpressionSyntax) {
 			expendValue := fmt.Sprintf("%s(%s)", function.Function, strings.Replace(function.Parameter, "$", "/"+item.ItemParent[0].Host+"/"+item.Key, 1))
 			trigger.Expression = strings.ReplaceAll(trigger.Expression, idstr, expendValue)
 			trigger.RecoveryExpression = strings.ReplaceAll(trigger.RecoveryExpression, idstr, expendValue)
@@ -253,6 +257,9 @@ func getTriggerParentID(api *zabbix.API, id string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if len(triggers) == 0 {
+		return "", newNotFoundError("Trigger %s doesn't exist", id)
+	}
 	if len(triggers) != 1 {
 		return "", fmt.Errorf("Expected one item and got %d items", len(triggers))
 	}
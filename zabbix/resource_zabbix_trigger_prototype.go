@@ -19,6 +19,7 @@ func resourceZabbixTriggerPrototype() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
@@ -69,7 +70,7 @@ func resourceZabbixTriggerPrototypeCreate(d *schema.ResourceData, meta interface
 }
 
 func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	params := zabbix.Params{
 		"output":             "extend",
@@ -80,7 +81,10 @@ func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}
 	}
 	res, err := api.TriggerPrototypesGet(params)
 	if err != nil {
-		return err
+		return readError(d, err, "Failed to read trigger prototype %s", d.Id())
+	}
+	if len(res) == 0 {
+		return readError(d, newNotFoundError("Trigger prototype %s doesn't exist", d.Id()), "Failed to read trigger prototype %s", d.Id())
 	}
 	if len(res) != 1 {
 		return fmt.Errorf("Expected one result got : %d", len(res))
@@ -102,11 +106,11 @@ func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}
 }
 
 func resourceZabbixTriggerPrototypeExist(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
 	_, err := api.TriggerPrototypeGetByID(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if isNotFoundError(err) {
 			log.Printf("Trigger prototype with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -125,9 +129,9 @@ func resourceZabbixTriggerPrototypeUpdate(d *schema.ResourceData, meta interface
 }
 
 func resourceZabbixTriggerPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).api
 
-	return deleteRetry(d.Id(), getTriggerPrototypeParentID, api.TriggerPrototypesDeleteIDs, api)
+	return deleteRetry(d.Id(), getTriggerPrototypeParentID, api.TriggerPrototypesDeleteIDs, api, d.Timeout(schema.TimeoutDelete))
 }
 
 func createTriggerPrototypeDependencies(d *schema.ResourceData) zabbix.TriggerPrototypes {
@@ -186,6 +190,9 @@ func getTriggerPrototypeParentID(api *zabbix.API, id string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if len(triggers) == 0 {
+		return "", newNotFoundError("Trigger prototype %s doesn't exist", id)
+	}
 	if len(triggers) != 1 {
 		return "", fmt.Errorf("Expected one trigger prototype and got %d trigger prototype", len(triggers))
 	}
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	"github.com/nzolot/go-zabbix-api"
 )
 
 func TestAccZabbixTrigger_Basic(t *testing.T) {
@@ -109,7 +108,7 @@ func TestAccZabbixTrigger_BasicDependencies(t *testing.T) {
 }
 
 func testAccCheckZabbixTriggerDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).api
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_trigger" {
@@ -1,6 +1,8 @@
 package zabbix
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
@@ -19,7 +21,6 @@ var interfaceSchema *schema.Resource = &schema.Resource{
 		"main": &schema.Schema{
 			Type:     schema.TypeBool,
 			Required: true,
-			ForceNew: true,
 		},
 		"port": &schema.Schema{
 			Type:     schema.TypeString,
@@ -31,12 +32,108 @@ var interfaceSchema *schema.Resource = &schema.Resource{
 			Type:     schema.TypeString,
 			Optional: true,
 			Default:  "agent",
-			ForceNew: true,
 		},
 		"interface_id": &schema.Schema{
 			Type:     schema.TypeString,
 			Computed: true,
-			ForceNew: true,
+		},
+		"details": &schema.Schema{
+			Type:        schema.TypeList,
+			MaxItems:    1,
+			Optional:    true,
+			Elem:        interfaceDetailsSchema,
+			Description: "SNMP settings of the interface, required by snmp interfaces (Zabbix >=5.0).",
+		},
+	},
+}
+
+var interfaceDetailsSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"version": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  2,
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				v := val.(int)
+				if v < 1 || v > 3 {
+					errs = append(errs, fmt.Errorf("%q, must be between 1 and 3 inclusive, got %d", key, v))
+				}
+				return
+			},
+			Description: "SNMP version: 1, 2 (v2c) or 3.",
+		},
+		"bulk": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Use bulk SNMP requests.",
+		},
+		"community": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMP community, required by SNMPv1 and SNMPv2c.",
+		},
+		"securityname": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "SNMPv3 security name.",
+		},
+		"securitylevel": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  0,
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				v := val.(int)
+				if v < 0 || v > 2 {
+					errs = append(errs, fmt.Errorf("%q, must be between 0 and 2 inclusive, got %d", key, v))
+				}
+				return
+			},
+			Description: "SNMPv3 security level: 0 (noAuthNoPriv), 1 (authNoPriv) or 2 (authPriv).",
+		},
+		"authprotocol": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  0,
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				v := val.(int)
+				if v < 0 || v > 5 {
+					errs = append(errs, fmt.Errorf("%q, must be between 0 and 5 inclusive, got %d", key, v))
+				}
+				return
+			},
+			Description: "SNMPv3 authentication protocol: 0 (MD5), 1 (SHA1), 2 (SHA224), 3 (SHA256), 4 (SHA384) or 5 (SHA512).",
+		},
+		"authpassphrase": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMPv3 authentication passphrase.",
+		},
+		"privprotocol": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  0,
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				v := val.(int)
+				if v < 0 || v > 5 {
+					errs = append(errs, fmt.Errorf("%q, must be between 0 and 5 inclusive, got %d", key, v))
+				}
+				return
+			},
+			Description: "SNMPv3 privacy protocol: 0 (DES), 1 (AES128), 2 (AES192), 3 (AES256), 4 (AES192C) or 5 (AES256C).",
+		},
+		"privpassphrase": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMPv3 privacy passphrase.",
+		},
+		"contextname": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "SNMPv3 context name.",
 		},
 	},
 }
@@ -102,3 +199,69 @@ var LLDMacroPathsSchema *schema.Resource = &schema.Resource{
 		},
 	},
 }
+
+var basicAuthSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"username": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"password": &schema.Schema{
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+	},
+}
+
+// HostTagOperators zabbix different operators of the tag conditions of host.get
+var HostTagOperators = map[string]int{
+	"contains":     0,
+	"equals":       1,
+	"not_contains": 2,
+	"not_equals":   3,
+	"exists":       4,
+	"not_exists":   5,
+}
+
+var hostTagFilterSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"tag": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of the tag.",
+		},
+		"value": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Value to compare the tag value to.",
+		},
+		"operator": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "equals",
+			ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+				if _, ok := HostTagOperators[val.(string)]; !ok {
+					errs = append(errs, fmt.Errorf("%q must be one of contains, equals, not_contains, not_equals, exists or not_exists, got %q", key, val))
+				}
+				return
+			},
+			Description: "Operator of the condition: contains, equals, not_contains, not_equals, exists or not_exists (the last four since Zabbix 6.0).",
+		},
+	},
+}
+
+// createHostTagFilters return the tags parameter of host.get of the tag conditions
+func createHostTagFilters(conditions []interface{}) []map[string]interface{} {
+	tags := make([]map[string]interface{}, len(conditions))
+	for i, c := range conditions {
+		condition := c.(map[string]interface{})
+		tags[i] = map[string]interface{}{
+			"tag":      condition["tag"].(string),
+			"value":    condition["value"].(string),
+			"operator": HostTagOperators[condition["operator"].(string)],
+		}
+	}
+	return tags
+}
@@ -0,0 +1,370 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logoutTimeout bounds the user.logout calls made when the plugin exits, well
+// under the 2 seconds terraform gives the plugin before killing it
+const logoutTimeout = time.Second
+
+// sessions are the sessions opened by the provider instances of this plugin
+var sessions struct {
+	sync.Mutex
+	list []*sessionTransport
+}
+
+// CloseSessions log out of the sessions opened by the provider, so they don't
+// pile up in the sessions table of the zabbix server. Called when the plugin
+// exits, the logout is best effort: the sessions left when logoutTimeout
+// expires are kept until the server expires them
+func CloseSessions() {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+	defer cancel()
+
+	for _, session := range sessions.list {
+		session.logout(ctx)
+	}
+	sessions.list = nil
+}
+
+func registerSession(session *sessionTransport) {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	sessions.list = append(sessions.list, session)
+}
+
+// sessionTransport share a single user.login session between all the API calls
+// of a provider instance. When the server reports the session as expired, it logs
+// in again with the credentials of the first user.login and replays the call once
+type sessionTransport struct {
+	url string
+
+	mutex       sync.Mutex
+	auth        string
+	loginBody   []byte
+	loginHeader http.Header
+
+	// deferredLogin is the user.login sent before the first call needing a
+	// session, when the provider is configured without calling the API
+	deferredLogin []byte
+	loginMutex    sync.Mutex
+
+	transport http.RoundTripper
+}
+
+type jsonRPCCall map[string]json.RawMessage
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var call jsonRPCCall
+	if err := json.Unmarshal(body, &call); err != nil {
+		return t.send(req, body)
+	}
+
+	switch jsonRPCMethod(body) {
+	case "user.login":
+		return t.login(req, body)
+	case "user.logout":
+		t.setSession("")
+		return t.send(req, body)
+	}
+
+	auth := t.session()
+
+	// calls without auth, like apiinfo.version, don't use the session unless the
+	// login is deferred and the API client has no session yet
+	if _, ok := call["auth"]; !ok {
+		if !t.isLoginDeferred() || strings.EqualFold(jsonRPCMethod(body), "apiinfo.version") {
+			return t.send(req, body)
+		}
+		if auth, err = t.loginNow(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	if auth == "" {
+		// the session isn't managed here, for instance with an API token
+		return t.send(req, body)
+	}
+
+	resp, err := t.sendWithSession(req, call, auth)
+	if err != nil || !t.canLogin() || !isSessionExpiredResponse(resp) {
+		return resp, err
+	}
+
+	log.Printf("[DEBUG] Zabbix session expired, logging in again")
+	newAuth, loginErr := t.relogin(req.Context(), auth)
+	if loginErr != nil {
+		log.Printf("[WARN] Failed to log in again to Zabbix: %v", loginErr)
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	return t.sendWithSession(req, call, newAuth)
+}
+
+// deferLogin postpone the user.login of the provider to the first call needing
+// a session. The session is then only known by the transport, which adds it to
+// the calls of the API client
+func (t *sessionTransport) deferLogin(usernameField, username, password string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "user.login",
+		"params": map[string]string{
+			usernameField: username,
+			"password":    password,
+		},
+		"id": 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	t.loginMutex.Lock()
+	defer t.loginMutex.Unlock()
+
+	t.deferredLogin = body
+	return nil
+}
+
+func (t *sessionTransport) isLoginDeferred() bool {
+	t.loginMutex.Lock()
+	defer t.loginMutex.Unlock()
+
+	return t.deferredLogin != nil
+}
+
+// loginNow send the deferred login once, the calls made meanwhile wait for its session
+func (t *sessionTransport) loginNow(ctx context.Context) (string, error) {
+	t.loginMutex.Lock()
+	defer t.loginMutex.Unlock()
+
+	if auth := t.session(); auth != "" {
+		return auth, nil
+	}
+
+	t.mutex.Lock()
+	t.loginHeader = http.Header{"Content-Type": []string{"application/json-rpc"}}
+	t.mutex.Unlock()
+
+	resp, err := t.call(ctx, t.deferredLogin)
+	if err != nil {
+		return "", fmt.Errorf("Failed to log in to Zabbix: %v", err)
+	}
+
+	auth, err := readJSONRPCResult(resp)
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("Failed to log in to Zabbix: %v", err)
+	}
+
+	t.mutex.Lock()
+	t.auth = auth
+	t.loginBody = t.deferredLogin
+	t.mutex.Unlock()
+
+	return auth, nil
+}
+
+func (t *sessionTransport) send(req *http.Request, body []byte) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	return t.transport.RoundTrip(req)
+}
+
+func (t *sessionTransport) sendWithSession(req *http.Request, call jsonRPCCall, auth string) (*http.Response, error) {
+	call["auth"], _ = json.Marshal(auth)
+	body, err := json.Marshal(call)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.send(req, body)
+}
+
+// login send the user.login of the API client and keep it to log in again later
+func (t *sessionTransport) login(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.send(req, body)
+	if err != nil {
+		return resp, err
+	}
+
+	auth, err := readJSONRPCResult(resp)
+	if err != nil {
+		// the API client reports the error from the response body
+		return resp, nil
+	}
+
+	t.mutex.Lock()
+	t.auth = auth
+	t.loginBody = body
+	t.loginHeader = req.Header.Clone()
+	t.mutex.Unlock()
+
+	return resp, nil
+}
+
+// relogin replace the expired session, unless another call already did it
+func (t *sessionTransport) relogin(ctx context.Context, expiredAuth string) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.auth != expiredAuth {
+		return t.auth, nil
+	}
+
+	resp, err := t.call(ctx, t.loginBody)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := readJSONRPCResult(resp)
+	resp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+
+	t.auth = auth
+	return auth, nil
+}
+
+func (t *sessionTransport) logout(ctx context.Context) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.auth == "" || t.loginBody == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		log.Printf("[WARN] Skipped the logout of a Zabbix session, the plugin is exiting")
+		return
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "user.logout",
+		"params":  []string{},
+		"auth":    t.auth,
+		"id":      1,
+	})
+
+	resp, err := t.call(ctx, body)
+	if err != nil {
+		log.Printf("[WARN] Failed to log out of Zabbix, the session is kept until it expires: %v", err)
+		return
+	}
+	resp.Body.Close()
+
+	t.auth = ""
+}
+
+// call send a JSON-RPC request built by the transport itself, with the headers of user.login
+func (t *sessionTransport) call(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range t.loginHeader {
+		req.Header[name] = values
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+func (t *sessionTransport) session() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.auth
+}
+
+func (t *sessionTransport) setSession(auth string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.auth = auth
+}
+
+func (t *sessionTransport) canLogin() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.loginBody != nil
+}
+
+// jsonRPCResponse is the part of the JSON-RPC responses used by the transports
+type jsonRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// peekJSONRPCResponse decode resp without consuming its body
+func peekJSONRPCResponse(resp *http.Response) (*jsonRPCResponse, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse jsonRPCResponse
+	if err := json.Unmarshal(body, &rpcResponse); err != nil {
+		return nil, err
+	}
+	return &rpcResponse, nil
+}
+
+func isSessionExpiredResponse(resp *http.Response) bool {
+	rpcResponse, err := peekJSONRPCResponse(resp)
+	if err != nil || rpcResponse.Error == nil {
+		return false
+	}
+	return categorizeErrorMessage(rpcResponse.Error.Data+" "+rpcResponse.Error.Message) == errorSessionExpired
+}
+
+func readJSONRPCResult(resp *http.Response) (string, error) {
+	rpcResponse, err := peekJSONRPCResponse(resp)
+	if err != nil {
+		return "", err
+	}
+	if rpcResponse.Error != nil {
+		return "", fmt.Errorf("%d (%s): %s", rpcResponse.Error.Code, rpcResponse.Error.Message, rpcResponse.Error.Data)
+	}
+
+	var result string
+	if err := json.Unmarshal(rpcResponse.Result, &result); err != nil || result == "" {
+		return "", errors.New("Unexpected user.login result")
+	}
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSessionTransportRelogin(t *testing.T) {
+	var logins, logouts int32
+	var mutex sync.Mutex
+	validAuth := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call struct {
+			Method string `json:"method"`
+			Auth   string `json:"auth"`
+			ID     int    `json:"id"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &call)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch {
+		case call.Method == "user.login":
+			n := atomic.AddInt32(&logins, 1)
+			validAuth = fmt.Sprintf("session%d", n)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"%s","id":%d}`, validAuth, call.ID)
+		case call.Method == "user.logout":
+			atomic.AddInt32(&logouts, 1)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":true,"id":%d}`, call.ID)
+		case call.Auth != validAuth:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Session terminated, re-login, please."},"id":%d}`, call.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[],"id":%d}`, call.ID)
+		}
+	}))
+	defer server.Close()
+
+	session := &sessionTransport{
+		url:       server.URL,
+		transport: http.DefaultTransport,
+	}
+	client := &http.Client{Transport: session}
+
+	post := func(body string) string {
+		resp, err := client.Post(server.URL, "application/json-rpc", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		content, _ := ioutil.ReadAll(resp.Body)
+		return string(content)
+	}
+
+	post(`{"jsonrpc":"2.0","method":"user.login","params":{"user":"Admin","password":"zabbix"},"id":1}`)
+
+	// the server forgets the session, every call must log in again only once
+	mutex.Lock()
+	validAuth = "expired"
+	mutex.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := post(`{"jsonrpc":"2.0","method":"host.get","params":{},"auth":"session1","id":2}`)
+			if resp != `{"jsonrpc":"2.0","result":[],"id":2}` {
+				t.Errorf("Unexpected response %s", resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&logins); n != 2 {
+		t.Fatalf("Got %d user.login calls, expected 2", n)
+	}
+
+	CloseSessions()
+	registerSession(session)
+	CloseSessions()
+
+	if n := atomic.LoadInt32(&logouts); n != 1 {
+		t.Fatalf("Got %d user.logout calls, expected 1", n)
+	}
+}
+
+func TestSessionTransportDeferredLogin(t *testing.T) {
+	var logins int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call struct {
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+			Auth   string            `json:"auth"`
+			ID     int               `json:"id"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &call)
+
+		switch {
+		case call.Method == "user.login":
+			if call.Params["username"] != "Admin" || call.Params["password"] != "zabbix" {
+				t.Errorf("Unexpected user.login params %v", call.Params)
+			}
+			atomic.AddInt32(&logins, 1)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"session1","id":%d}`, call.ID)
+		case call.Method == "apiinfo.version":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"6.0.0","id":%d}`, call.ID)
+		case call.Auth != "session1":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Not authorised."},"id":%d}`, call.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[],"id":%d}`, call.ID)
+		}
+	}))
+	defer server.Close()
+
+	session := &sessionTransport{
+		url:       server.URL,
+		transport: http.DefaultTransport,
+	}
+	client := &http.Client{Transport: session}
+
+	post := func(body string) string {
+		resp, err := client.Post(server.URL, "application/json-rpc", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		content, _ := ioutil.ReadAll(resp.Body)
+		return string(content)
+	}
+
+	if err := session.deferLogin("username", "Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+
+	post(`{"jsonrpc":"2.0","method":"apiinfo.version","params":{},"id":2}`)
+	if n := atomic.LoadInt32(&logins); n != 0 {
+		t.Fatalf("Got %d user.login calls before the first call with a session, expected 0", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := post(`{"jsonrpc":"2.0","method":"host.get","params":{},"id":3}`)
+			if resp != `{"jsonrpc":"2.0","result":[],"id":3}` {
+				t.Errorf("Unexpected response %s", resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&logins); n != 1 {
+		t.Fatalf("Got %d user.login calls, expected 1", n)
+	}
+}
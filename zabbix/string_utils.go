@@ -3,6 +3,7 @@ package zabbix
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"strconv"
 )
 
 func randomHex(n int) (string, error) {
@@ -12,3 +13,9 @@ func randomHex(n int) (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// atoiOrZero convert the numbers returned as strings by the API, 0 when empty or invalid
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,472 @@
+package zabbix
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// createHTTPClient build the http client used by the zabbix API from the provider settings
+func createHTTPClient(d *schema.ResourceData) (*http.Client, error) {
+	transport, err := createHTTPTransport(d)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(d.Get("request_timeout").(int)) * time.Second,
+	}
+
+	headers := d.Get("http_headers").(map[string]interface{})
+	basicAuth := d.Get("basic_auth").([]interface{})
+	if len(headers) > 0 || len(basicAuth) > 0 {
+		headerTransport := &headerTransport{
+			headers:   make(map[string]string, len(headers)),
+			transport: httpClient.Transport,
+		}
+		for name, value := range headers {
+			headerTransport.headers[name] = value.(string)
+		}
+		if len(basicAuth) > 0 && basicAuth[0] != nil {
+			auth := basicAuth[0].(map[string]interface{})
+			headerTransport.username = auth["username"].(string)
+			headerTransport.password = auth["password"].(string)
+		}
+		httpClient.Transport = headerTransport
+	}
+
+	if logging.IsDebugOrHigher() {
+		httpClient.Transport = &redactingTransport{
+			name:      "Zabbix",
+			transport: httpClient.Transport,
+		}
+	}
+
+	maxConcurrentRequests := d.Get("max_concurrent_requests").(int)
+	requestsPerSecond := d.Get("requests_per_second").(float64)
+	if maxConcurrentRequests > 0 || requestsPerSecond > 0 {
+		throttleTransport := &throttleTransport{
+			transport: httpClient.Transport,
+		}
+		if maxConcurrentRequests > 0 {
+			throttleTransport.slots = make(chan struct{}, maxConcurrentRequests)
+		}
+		if requestsPerSecond > 0 {
+			throttleTransport.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+		}
+		httpClient.Transport = throttleTransport
+	}
+
+	httpClient.Transport = &retryTransport{
+		maxRetries: d.Get("max_retries").(int),
+		backoff:    time.Duration(d.Get("retry_backoff").(int)) * time.Second,
+		transport:  httpClient.Transport,
+	}
+
+	session := &sessionTransport{
+		url:       d.Get("server_url").(string),
+		transport: httpClient.Transport,
+	}
+	registerSession(session)
+	httpClient.Transport = session
+
+	return &httpClient, nil
+}
+
+func createHTTPTransport(d *schema.ResourceData) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := createTLSConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if proxyURL := d.Get("proxy_url").(string); proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid proxy_url %q: %v", proxyURL, err)
+		}
+		log.Printf("[DEBUG] Zabbix API requests will use proxy %s", u.Host)
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	return transport, nil
+}
+
+func createTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+
+	caPEM := []byte(d.Get("ca_pem").(string))
+	if caFile := d.Get("ca_file").(string); caFile != "" {
+		content, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read ca_file %s: %v", caFile, err)
+		}
+		caPEM = content
+	}
+
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] Failed to load system cert pool, only the configured CA will be trusted: %v", err)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("No valid PEM certificate found in ca_file or ca_pem")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certFile := d.Get("client_cert_file").(string)
+	keyFile := d.Get("client_key_file").(string)
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("client_cert_file and client_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// headerTransport add the configured headers and basic auth credentials to
+// every request sent to the zabbix API
+type headerTransport struct {
+	headers   map[string]string
+	username  string
+	password  string
+	transport http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it was given
+	req = req.Clone(req.Context())
+
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	if t.username != "" {
+		req.SetBasicAuth(t.username, t.password)
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// redactedValue replace secrets in the debug logs
+const redactedValue = "<redacted>"
+
+// sensitiveJSONRPCKeys are the request and response fields that are never logged
+var sensitiveJSONRPCKeys = map[string]bool{
+	"auth":           true,
+	"password":       true,
+	"passwd":         true,
+	"sessionid":      true,
+	"token":          true,
+	"tls_psk":        true,
+	"ipmi_password":  true,
+	"community":      true,
+	"authpassphrase": true,
+	"privpassphrase": true,
+}
+
+// sensitiveJSONRPCResults are the methods whose whole result is a secret
+var sensitiveJSONRPCResults = map[string]bool{
+	"user.login":     true,
+	"token.generate": true,
+}
+
+// redactingTransport log the zabbix API calls like logging.NewTransport but
+// mask credentials, session tokens, PSKs and macro values. Headers are not logged
+type redactingTransport struct {
+	name      string
+	transport http.RoundTripper
+}
+
+func (t *redactingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = body
+
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	method := jsonRPCMethod(reqBody)
+	log.Printf("[DEBUG] %s API Request %s: %s", t.name, method, redactJSONRPC(reqBody, method))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Printf("[DEBUG] %s API Request %s failed after %s: %v", t.name, method, elapsed, err)
+		return resp, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	log.Printf("[DEBUG] %s API Response %s (%s in %s): %s", t.name, method, resp.Status, elapsed, redactJSONRPC(respBody, method))
+
+	return resp, nil
+}
+
+func jsonRPCMethod(body []byte) string {
+	var call struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &call); err != nil || call.Method == "" {
+		return "<unknown>"
+	}
+	return call.Method
+}
+
+// redactJSONRPC return the JSON-RPC payload with all the secrets masked,
+// payloads which can't be parsed are not logged at all
+func redactJSONRPC(body []byte, method string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Sprintf("<%d bytes of non JSON content>", len(body))
+	}
+
+	if call, ok := payload.(map[string]interface{}); ok {
+		if _, ok := call["result"]; ok && sensitiveJSONRPCResults[method] {
+			call["result"] = redactedValue
+		}
+	}
+
+	redacted, err := json.Marshal(redactJSONValue(payload))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes of non JSON content>", len(body))
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		_, isMacro := v["macro"]
+		for key, child := range v {
+			if sensitiveJSONRPCKeys[key] || (isMacro && key == "value") {
+				if child != nil && child != "" {
+					v[key] = redactedValue
+				}
+				continue
+			}
+			v[key] = redactJSONValue(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactJSONValue(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// maxRetryDelay caps the exponential backoff between two retries
+const maxRetryDelay = 30 * time.Second
+
+// retryTransport replay the API calls failing with a transient error of the
+// network or of the frontend. Calls changing objects are only replayed when the
+// server didn't process them, the database errors are retried by the resources
+// until their timeout expires
+type retryTransport struct {
+	maxRetries int
+	backoff    time.Duration
+	transport  http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		content, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = content
+	}
+	method := jsonRPCMethod(body)
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if body != nil {
+			attemptReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.transport.RoundTrip(attemptReq)
+
+		reason := transientFailure(method, resp, err)
+		if reason == "" && !isReadOnlyMethod(method) && (err != nil || resp.StatusCode >= 500) {
+			log.Printf("[WARN] Zabbix API call %s failed and is not retried, the server may have run it", method)
+		}
+		if reason == "" || attempt >= t.maxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		delay := retryDelay(t.backoff, attempt)
+		log.Printf("[WARN] Zabbix API call %s failed with %s, retry %d/%d in %s", method, reason, attempt+1, t.maxRetries, delay)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isReadOnlyMethod tell if replaying the API call can't change anything on the server
+func isReadOnlyMethod(method string) bool {
+	method = strings.ToLower(method)
+	return strings.HasSuffix(method, ".get") || method == "apiinfo.version"
+}
+
+// transientFailure return why the API call should be retried, or an empty
+// string when it succeeded, failed permanently or may have been processed
+// by the server while it isn't safe to replay
+func transientFailure(method string, resp *http.Response, err error) string {
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		var invalidCertificate x509.CertificateInvalidError
+		var invalidHostname x509.HostnameError
+		if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCertificate) || errors.As(err, &invalidHostname) {
+			return ""
+		}
+
+		// the connection failed, the request never reached the server
+		var opError *net.OpError
+		if errors.As(err, &opError) && opError.Op == "dial" {
+			return err.Error()
+		}
+		if isReadOnlyMethod(method) {
+			return err.Error()
+		}
+		return ""
+	}
+
+	// 503 is answered by the frontend or a load balancer without running the call
+	if resp.StatusCode == http.StatusServiceUnavailable || (resp.StatusCode >= 500 && isReadOnlyMethod(method)) {
+		return fmt.Sprintf("HTTP status %s", resp.Status)
+	}
+
+	return ""
+}
+
+// retryDelay return the exponential backoff of the attempt with a random jitter,
+// so concurrent calls failing together don't retry at the same time
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+
+	delay := backoff
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// throttleTransport limit the number of API calls running at the same time and
+// the rate at which they are sent. It is shared by all the resources of a
+// provider instance, so it protects the frontend whatever the terraform parallelism
+type throttleTransport struct {
+	// slots is a semaphore of max_concurrent_requests, nil when unlimited
+	slots chan struct{}
+	// interval is the minimum delay between two requests, 0 when unlimited
+	interval time.Duration
+
+	mutex sync.Mutex
+	next  time.Time
+
+	transport http.RoundTripper
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if wait := t.reserve(); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// reserve book the next request slot and return how long to wait for it
+func (t *throttleTransport) reserve() time.Duration {
+	if t.interval <= 0 {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	wait := t.next.Sub(now)
+	t.next = t.next.Add(t.interval)
+
+	return wait
+}
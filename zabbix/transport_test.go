@@ -0,0 +1,229 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRedactJSONRPC(t *testing.T) {
+	cases := []struct {
+		name     string
+		method   string
+		body     string
+		hidden   []string
+		expected []string
+	}{
+		{
+			name:     "login request",
+			method:   "user.login",
+			body:     `{"jsonrpc":"2.0","method":"user.login","params":{"user":"Admin","password":"zabbix"},"id":1}`,
+			hidden:   []string{"zabbix"},
+			expected: []string{`"method":"user.login"`, `"user":"Admin"`},
+		},
+		{
+			name:     "login response",
+			method:   "user.login",
+			body:     `{"jsonrpc":"2.0","result":"0424bd59b807674191e7d77572075f33","id":1}`,
+			hidden:   []string{"0424bd59b807674191e7d77572075f33"},
+			expected: []string{`"id":1`},
+		},
+		{
+			name:     "session token and macros",
+			method:   "host.create",
+			body:     `{"jsonrpc":"2.0","method":"host.create","params":[{"host":"web01","macros":[{"macro":"{$SECRET}","value":"s3cr3t"}]}],"auth":"0424bd59b807674191e7d77572075f33","id":2}`,
+			hidden:   []string{"s3cr3t", "0424bd59b807674191e7d77572075f33"},
+			expected: []string{`"host":"web01"`, `"macro":"{$SECRET}"`},
+		},
+		{
+			name:     "proxy psk",
+			method:   "proxy.create",
+			body:     `{"jsonrpc":"2.0","method":"proxy.create","params":[{"host":"proxy01","tls_psk":"af8ced32dfe8714e548694e2d29e1a14"}],"id":3}`,
+			hidden:   []string{"af8ced32dfe8714e548694e2d29e1a14"},
+			expected: []string{`"host":"proxy01"`},
+		},
+		{
+			name:     "not json",
+			method:   "<unknown>",
+			body:     `password=zabbix`,
+			hidden:   []string{"zabbix"},
+			expected: []string{"non JSON content"},
+		},
+	}
+
+	for _, c := range cases {
+		redacted := redactJSONRPC([]byte(c.body), c.method)
+		for _, secret := range c.hidden {
+			if strings.Contains(redacted, secret) {
+				t.Errorf("%s: %q leaked in %s", c.name, secret, redacted)
+			}
+		}
+		for _, value := range c.expected {
+			if !strings.Contains(redacted, value) {
+				t.Errorf("%s: expected %s in %s", c.name, value, redacted)
+			}
+		}
+	}
+}
+
+func TestJSONRPCMethod(t *testing.T) {
+	if method := jsonRPCMethod([]byte(`{"jsonrpc":"2.0","method":"host.get","params":{},"id":1}`)); method != "host.get" {
+		t.Fatalf("Got method %q, expected host.get", method)
+	}
+	if method := jsonRPCMethod([]byte(`not json`)); method != "<unknown>" {
+		t.Fatalf("Got method %q, expected <unknown>", method)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestThrottleTransport(t *testing.T) {
+	var running, maxRunning int32
+	transport := &throttleTransport{
+		slots:    make(chan struct{}, 2),
+		interval: 5 * time.Millisecond,
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("POST", "http://localhost/api_jsonrpc.php", nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning > 2 {
+		t.Fatalf("Got %d concurrent requests, expected at most 2", maxRunning)
+	}
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Fatalf("10 requests took %s, expected at least 45ms with a 5ms interval", elapsed)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	cases := []struct {
+		method   string
+		status   int
+		err      error
+		expected int
+	}{
+		{"host.get", http.StatusOK, nil, 1},
+		{"host.get", http.StatusBadGateway, nil, 3},
+		{"host.get", 0, reset, 3},
+		{"host.create", http.StatusServiceUnavailable, nil, 3},
+		{"host.create", 0, refused, 3},
+		{"host.create", http.StatusBadGateway, nil, 1},
+		{"host.update", http.StatusGatewayTimeout, nil, 1},
+		{"host.update", 0, reset, 1},
+		{"host.get", http.StatusNotFound, nil, 1},
+	}
+
+	for _, c := range cases {
+		var calls int32
+		transport := &retryTransport{
+			maxRetries: 2,
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&calls, 1)
+				body, _ := ioutil.ReadAll(req.Body)
+				if method := jsonRPCMethod(body); method != c.method {
+					t.Errorf("Got method %s on replay, expected %s", method, c.method)
+				}
+				if c.err != nil {
+					return nil, c.err
+				}
+				return &http.Response{StatusCode: c.status, Status: http.StatusText(c.status), Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+			}),
+		}
+
+		req, _ := http.NewRequest("POST", "http://localhost/api_jsonrpc.php", strings.NewReader(`{"jsonrpc":"2.0","method":"`+c.method+`","params":{},"id":1}`))
+		transport.RoundTrip(req)
+
+		if calls != int32(c.expected) {
+			t.Errorf("%s with status %d and error %v: got %d calls, expected %d", c.method, c.status, c.err, calls, c.expected)
+		}
+	}
+}
+
+func TestRetryTransportContextCanceled(t *testing.T) {
+	var calls int32
+	transport := &retryTransport{
+		maxRetries: 5,
+		backoff:    time.Hour,
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "POST", "http://localhost/api_jsonrpc.php", strings.NewReader(`{"method":"host.get"}`))
+
+	start := time.Now()
+	if _, err := transport.RoundTrip(req); err != context.DeadlineExceeded {
+		t.Fatalf("Got error %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Retry took %s after the context was canceled", elapsed)
+	}
+	if calls != 1 {
+		t.Fatalf("Got %d calls, expected 1", calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		backoff time.Duration
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{time.Second, 0, 500 * time.Millisecond, time.Second},
+		{time.Second, 1, time.Second, 2 * time.Second},
+		{time.Second, 3, 4 * time.Second, 8 * time.Second},
+		{time.Second, 5, 15 * time.Second, maxRetryDelay},
+		{time.Second, 100, 15 * time.Second, maxRetryDelay},
+		{time.Minute, 0, 15 * time.Second, maxRetryDelay},
+		{0, 3, 0, 0},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			if delay := retryDelay(c.backoff, c.attempt); delay < c.min || delay > c.max {
+				t.Fatalf("Backoff %s attempt %d: got %s, expected between %s and %s", c.backoff, c.attempt, delay, c.min, c.max)
+			}
+		}
+	}
+}